@@ -0,0 +1,376 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// AsyncPolicy selects what an AsyncHandler does with a Record when its queue is full
+type AsyncPolicy int
+
+const (
+	// AsyncBlock waits for space in the queue, so that no records are dropped
+	AsyncBlock AsyncPolicy = iota
+
+	// AsyncDropNewest drops the new record
+	AsyncDropNewest AsyncPolicy = iota
+
+	// AsyncDropOldest drops the oldest queued record to make room for the new record. Records at LogLevelPanic
+	// and LogLevelFatal are not dropped.
+	AsyncDropOldest AsyncPolicy = iota
+
+	// AsyncDropBelowLevel drops the new record if it is less severe than AsyncOptions.DropLevel; otherwise
+	// it waits for space in the queue
+	AsyncDropBelowLevel AsyncPolicy = iota
+)
+
+// defaultAsyncQueueSize is the queue size of an AsyncHandler if none is configured
+const defaultAsyncQueueSize = 1024
+
+// AsyncOptions configures an AsyncHandler
+type AsyncOptions struct {
+	// QueueSize is the maximum number of records waiting to be written. If zero, 1024 is used.
+	QueueSize int
+
+	// Policy selects what happens to a record when the queue is full. The default is AsyncBlock.
+	Policy AsyncPolicy
+
+	// DropLevel is the least severe level that is not dropped under AsyncDropBelowLevel. For example, with
+	// LogLevelWarning, info, debug and trace records are dropped when the queue is full.
+	DropLevel LogLevel
+
+	// ErrorHandler, if not nil, is called on the background goroutine with the wrapped Handler when it fails
+	// to handle a record. If it is nil, WithAsync reports failures to the logger (see WithErrorHandler and
+	// BasicLogger.Stats).
+	ErrorHandler ErrorHandler
+}
+
+// AsyncHandler is a Handler that queues Records and passes them to another Handler on a background
+// goroutine, so that logging does not wait for slow output such as a busy disk. When the queue is full,
+// records are dropped or the logging goroutine waits, according to the AsyncPolicy. Records at
+// LogLevelPanic and LogLevelFatal are never dropped, are queued even if the queue is full, and are written
+// before Handle returns, so that they are not lost when the program exits.
+//
+// Records are passed to the wrapped Handler in the order in which they were queued. Since they are passed on
+// a different goroutine, a wrapped Handler that reports the caller's location using Record.CallDepth, such as
+// one that writes to a RawLogger, reports an incorrect location; Record.PC is unaffected. Errors returned by
+// the wrapped Handler are not returned by Handle; they are passed to AsyncOptions.ErrorHandler instead.
+//
+// Flush waits for queued records to be written, and Close flushes and stops the background goroutine.
+type AsyncHandler struct {
+	handler      Handler
+	errorHandler ErrorHandler
+	policy       AsyncPolicy
+	dropLevel    LogLevel
+	queueSize    int
+	dropped      atomic.Uint64
+
+	mu sync.Mutex
+	// queue holds the records waiting to be written, in order of their sequence numbers
+	queue []asyncEntry
+	// nextSeq is the sequence number of the next record to be queued
+	nextSeq uint64
+	// writing is the sequence number of the record being written, or 0 if none is
+	writing uint64
+	// nextTicket and serving order the goroutines waiting for space in the queue, so that they are served in
+	// the order in which they arrived
+	nextTicket uint64
+	serving    uint64
+	// queued is closed and replaced when a record is added to an empty queue, or the AsyncHandler is stopped
+	queued chan struct{}
+	// space is closed and replaced when a record is taken from the queue, or the AsyncHandler is closed
+	space chan struct{}
+	// progress is closed and replaced when the oldest outstanding record is written or dropped, or the
+	// AsyncHandler is stopped
+	progress chan struct{}
+	closed   bool
+	stopped  bool
+}
+
+// asyncEntry is a queued Record and its sequence number
+type asyncEntry struct {
+	seq    uint64
+	r      *Record
+	urgent bool
+}
+
+// NewAsyncHandler creates an AsyncHandler that passes Records to a Handler on a background goroutine
+func NewAsyncHandler(handler Handler, opts AsyncOptions) *AsyncHandler {
+	queueSize := opts.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultAsyncQueueSize
+	}
+	h := &AsyncHandler{
+		handler:      handler,
+		errorHandler: opts.ErrorHandler,
+		policy:       opts.Policy,
+		dropLevel:    opts.DropLevel,
+		queueSize:    queueSize,
+		nextSeq:      1,
+		queued:       make(chan struct{}),
+		space:        make(chan struct{}),
+		progress:     make(chan struct{}),
+	}
+	go h.run()
+	return h
+}
+
+// run passes queued records to the wrapped Handler until the AsyncHandler is stopped
+func (h *AsyncHandler) run() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for {
+		for len(h.queue) == 0 && !h.stopped {
+			h.wait(h.queued)
+		}
+		if h.stopped {
+			return
+		}
+		e := h.queue[0]
+		h.queue[0] = asyncEntry{}
+		h.queue = h.queue[1:]
+		h.writing = e.seq
+		notify(&h.space)
+		h.mu.Unlock()
+		err := h.handler.Handle(e.r)
+		if err != nil && h.errorHandler != nil {
+			h.errorHandler(h.handler, e.r, err)
+		}
+		h.mu.Lock()
+		h.writing = 0
+		notify(&h.progress)
+	}
+}
+
+// notify wakes all goroutines waiting on a channel, by closing it and replacing it. h.mu must be held.
+func notify(ch *chan struct{}) {
+	close(*ch)
+	*ch = make(chan struct{})
+}
+
+// wait releases h.mu until a channel is closed. h.mu must be held.
+func (h *AsyncHandler) wait(ch chan struct{}) {
+	h.mu.Unlock()
+	<-ch
+	h.mu.Lock()
+}
+
+// written returns true if every record with a sequence number up to seq has been written or dropped. h.mu
+// must be held.
+func (h *AsyncHandler) written(seq uint64) bool {
+	return (h.writing == 0 || h.writing > seq) && (len(h.queue) == 0 || h.queue[0].seq > seq)
+}
+
+// waitWritten waits until every record with a sequence number up to seq has been written or dropped, or until
+// the context is done, in which case the context's error is returned
+func (h *AsyncHandler) waitWritten(ctx context.Context, seq uint64) error {
+	h.mu.Lock()
+	for !h.written(seq) {
+		progress := h.progress
+		h.mu.Unlock()
+		select {
+		case <-progress:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		h.mu.Lock()
+	}
+	h.mu.Unlock()
+	return nil
+}
+
+// dropOldest removes the oldest record that is not urgent from the queue, and returns false if every queued
+// record is urgent. h.mu must be held.
+func (h *AsyncHandler) dropOldest() bool {
+	for i := range h.queue {
+		if !h.queue[i].urgent {
+			if i == 0 && h.writing == 0 {
+				notify(&h.progress)
+			}
+			copy(h.queue[i:], h.queue[i+1:])
+			h.queue[len(h.queue)-1] = asyncEntry{}
+			h.queue = h.queue[:len(h.queue)-1]
+			h.dropped.Add(1)
+			return true
+		}
+	}
+	return false
+}
+
+// Handle queues a Record to be passed to the wrapped Handler, or drops it if the queue is full and the
+// policy allows. Records at LogLevelPanic and LogLevelFatal are written before Handle returns. Handle
+// returns os.ErrClosed if the AsyncHandler has been closed.
+func (h *AsyncHandler) Handle(r *Record) error {
+	urgent := r.Level != LogLevelUnknown && r.Level <= LogLevelFatal
+
+	h.mu.Lock()
+	if !urgent && !h.closed && len(h.queue) >= h.queueSize {
+		drop := false
+		switch h.policy {
+		case AsyncDropNewest:
+			drop = true
+		case AsyncDropOldest:
+			// If every queued record is urgent, the new record is dropped instead
+			drop = !h.dropOldest()
+		case AsyncDropBelowLevel:
+			drop = r.Level > h.dropLevel
+		}
+		if drop {
+			h.mu.Unlock()
+			h.dropped.Add(1)
+			return nil
+		}
+	}
+	if !urgent && (len(h.queue) >= h.queueSize || h.serving != h.nextTicket) {
+		ticket := h.nextTicket
+		h.nextTicket++
+		for !h.closed && (len(h.queue) >= h.queueSize || h.serving != ticket) {
+			h.wait(h.space)
+		}
+		h.serving++
+		notify(&h.space)
+	}
+	if h.closed {
+		h.mu.Unlock()
+		return os.ErrClosed
+	}
+	seq := h.nextSeq
+	h.nextSeq++
+	h.queue = append(h.queue, asyncEntry{seq: seq, r: r, urgent: urgent})
+	if len(h.queue) == 1 {
+		notify(&h.queued)
+	}
+	h.mu.Unlock()
+
+	if urgent {
+		return h.waitWritten(context.Background(), seq)
+	}
+	return nil
+}
+
+// Flush waits until all records queued before Flush was called have been written, or until the context is
+// done, in which case the context's error is returned. Records queued while Flush is waiting are not waited for.
+func (h *AsyncHandler) Flush(ctx context.Context) error {
+	h.mu.Lock()
+	seq := h.nextSeq - 1
+	h.mu.Unlock()
+	return h.waitWritten(ctx, seq)
+}
+
+// Close stops accepting records, waits until all queued records have been written or until the context is
+// done, and then stops the background goroutine. If the context is done first, the remaining records are
+// discarded and the context's error is returned. Subsequent records fail with os.ErrClosed.
+func (h *AsyncHandler) Close(ctx context.Context) error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+	notify(&h.space)
+	h.mu.Unlock()
+	err := h.Flush(ctx)
+	h.mu.Lock()
+	h.stopped = true
+	h.queue = nil
+	notify(&h.queued)
+	notify(&h.progress)
+	h.mu.Unlock()
+	return err
+}
+
+// Dropped returns the number of records that have been dropped because the queue was full
+func (h *AsyncHandler) Dropped() uint64 {
+	return h.dropped.Load()
+}
+
+// Queued returns the number of records waiting to be written
+func (h *AsyncHandler) Queued() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.queue)
+}
+
+// GetLogLevel returns the log level of the wrapped Handler if it implements GetLogLeveler; otherwise
+// LogLevelTrace
+func (h *AsyncHandler) GetLogLevel() LogLevel {
+	gll, ok := h.handler.(GetLogLeveler)
+	if ok {
+		return gll.GetLogLevel()
+	}
+	return LogLevelTrace
+}
+
+// asyncFlushCloser is the interface of an AsyncHandler used by BasicLogger.Flush and BasicLogger.Close
+type asyncFlushCloser interface {
+	Flush(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+// Flush waits until all records queued by the logger's Handler, if it is an AsyncHandler (see WithAsync),
+// have been written, or until the context is done
+func (l *BasicLogger) Flush(ctx context.Context) error {
+	fc, ok := l.handler.(asyncFlushCloser)
+	if !ok {
+		return nil
+	}
+	return fc.Flush(ctx)
+}
+
+// Close closes the logger's Handler, if it is an AsyncHandler (see WithAsync), waiting until all queued
+// records have been written or until the context is done. Since the Handler is shared with all loggers
+// forked from this logger, they can no longer log after Close.
+func (l *BasicLogger) Close(ctx context.Context) error {
+	fc, ok := l.handler.(asyncFlushCloser)
+	if !ok {
+		return nil
+	}
+	return fc.Close(ctx)
+}
+
+// Flusher is implemented by loggers that can wait for queued records to be written, such as *BasicLogger
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// Closer is implemented by loggers that can write queued records and close their destination, such as
+// *BasicLogger
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// Flush waits until all records queued by a logger created with WithAsync have been written, or until the
+// context is done. It allows the Logger returned by New to be flushed without a type assertion, and does
+// nothing if lg does not implement Flusher.
+func Flush(lg Logger, ctx context.Context) error {
+	f, ok := lg.(Flusher)
+	if !ok {
+		return nil
+	}
+	return f.Flush(ctx)
+}
+
+// Close closes the Handler of a logger created with WithAsync, waiting until all queued records have been
+// written or until the context is done. It allows the Logger returned by New to be closed without a type
+// assertion, and does nothing if lg does not implement Closer.
+func Close(lg Logger, ctx context.Context) error {
+	c, ok := lg.(Closer)
+	if !ok {
+		return nil
+	}
+	return c.Close(ctx)
+}
+
+// WithAsync causes the new logger to queue records and write them on a background goroutine, using an
+// AsyncHandler with the given options to wrap the logger's destination. Use Flush and Close (or the
+// BasicLogger methods of the same names) to ensure that queued records are written before the program
+// exits. Records dropped because the queue is full are counted in BasicLogger.Stats. Unless
+// opts.ErrorHandler is set, records that the destination fails to write are reported to the logger's
+// ErrorHandler and statistics on the background goroutine (see WithErrorHandler and BasicLogger.Stats).
+func WithAsync(opts AsyncOptions) ConfigOption {
+	return func(cfg *Config) {
+		cfg.asyncOpts = &opts
+	}
+}
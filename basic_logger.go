@@ -4,19 +4,32 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"runtime"
+	"strings"
+	"time"
 )
 
 // BasicLogger is a logical log output stream with a level filter
-// and a prefix added to each output record.
+// and a prefix added to each output record. Output is emitted as
+// structured Records to a Handler.
 type BasicLogger struct {
 	// prefix includes all of the inherited prefixes, delimited with ": ". It
 	// is an empty string if this is the top level.
 	prefix string
 	// prefixC is prefix if prefix is empty; otherwise prefix + ": "
 	prefixC string
-	// logger is the raw logger
-	logger   RawLogger
-	logLevel LogLevel
+	// prefixPath contains the individual inherited prefixes, outermost first. The
+	// slice is shared with forked loggers and must not be modified.
+	prefixPath []string
+	// handler receives all output records
+	handler Handler
+	// level is the log level, which may be inherited from the logger this logger was forked from
+	level *LevelVar
+	// fields includes all of the inherited structured fields. It is nil if no fields have been added.
+	// The slice is shared with forked loggers and must not be modified.
+	fields []Field
+	// sink holds the ErrorHandler and statistics for the handler, shared with forked loggers
+	sink *sinkState
 }
 
 // CdRawOutput is the lowest level log output method; it writes the output for a logging event
@@ -26,17 +39,52 @@ type BasicLogger struct {
 // recover the call frame, PC, filename, etc.  If set to 1, it will display context for the
 // immediate caller of CdRawOutput; if set to 2, the caller's caller, etc.
 func (l *BasicLogger) CdRawOutput(calldepth int, s string) {
-	l.logger.Output(calldepth+1, s)
+	l.emit(calldepth+1, LogLevelUnknown, nil, s, nil)
 }
 
 // Output is the compatible with log.Logger.Output, to make this a RawLogger
 func (l *BasicLogger) Output(calldepth int, s string) error {
-	return l.logger.Output(calldepth+1, l.Sprint(s))
+	return l.emit(calldepth+1, LogLevelUnknown, l.prefixPath, s, l.fields)
+}
+
+// Handle adds the logger's prefix and fields to a Record emitted by another logger, and passes it to
+// this logger's Handler. This allows a BasicLogger to be used as the base of another logger without
+// flattening the Record to text. No level filtering is applied.
+func (l *BasicLogger) Handle(r *Record) error {
+	rr := *r
+	if len(l.prefixPath) > 0 {
+		rr.PrefixPath = make([]string, 0, len(l.prefixPath)+len(r.PrefixPath))
+		rr.PrefixPath = append(rr.PrefixPath, l.prefixPath...)
+		rr.PrefixPath = append(rr.PrefixPath, r.PrefixPath...)
+	}
+	rr.Fields = joinFields(l.fields, r.Fields)
+	rr.CallDepth++
+	err := l.handler.Handle(&rr)
+	return l.sink.done(l.handler, &rr, err)
+}
+
+// emit creates a Record for a logging event and passes it to the logger's Handler, calling the ErrorHandler
+// if it fails. Calldepth is used to recover the caller's PC. If set to 1, the record will refer to the
+// immediate caller of emit.
+func (l *BasicLogger) emit(calldepth int, logLevel LogLevel, prefixPath []string, msg string, fields []Field) error {
+	var pcs [1]uintptr
+	runtime.Callers(calldepth+1, pcs[:])
+	r := &Record{
+		Time:       time.Now(),
+		Level:      logLevel,
+		PrefixPath: prefixPath,
+		Message:    msg,
+		PC:         pcs[0],
+		Fields:     fields,
+		CallDepth:  calldepth + 1,
+	}
+	err := l.handler.Handle(r)
+	return l.sink.done(l.handler, r, err)
 }
 
 // CdPrint writes arguments to a Logger with a provided call depth. Arguments are formatted in the style of fmt.Sprint()
 func (l *BasicLogger) CdPrint(calldepth int, args ...interface{}) {
-	l.CdRawOutput(calldepth+1, l.Sprint(args...))
+	l.emit(calldepth+1, LogLevelUnknown, l.prefixPath, fmt.Sprint(args...), l.fields)
 }
 
 // Print outputs to a Logger in the style of fmt.Sprint()
@@ -46,7 +94,7 @@ func (l *BasicLogger) Print(args ...interface{}) {
 
 // CdPrintf outputs formatted text to a Logger with a provided call depth, in the style of fmt.Sprintf
 func (l *BasicLogger) CdPrintf(calldepth int, f string, args ...interface{}) {
-	l.CdRawOutput(calldepth+1, l.Sprintf(f, args...))
+	l.emit(calldepth+1, LogLevelUnknown, l.prefixPath, fmt.Sprintf(f, args...), l.fields)
 }
 
 // Printf outputs to a Logger in the style of fmt.Sprintf
@@ -58,14 +106,24 @@ func (l *BasicLogger) Printf(f string, args ...interface{}) {
 // logger's prefix) if the given logLevel is enabled. Then,
 // if the given logLevel is LogLevelPanic or LogLevelFatal, exits appropriately.
 func (l *BasicLogger) CdLogStrNoPrefix(calldepth int, logLevel LogLevel, s string) {
-	if logLevel <= l.logLevel || logLevel <= LogLevelFatal {
+	l.cdLogStr(calldepth+1, logLevel, nil, s, nil)
+}
+
+// cdLogStr emits a single message with the given prefix path, the logger's fields and any additional fields
+// to a Logger with provided call depth if the given logLevel is enabled. Then, if the given logLevel is
+// LogLevelPanic or LogLevelFatal, exits appropriately.
+func (l *BasicLogger) cdLogStr(calldepth int, logLevel LogLevel, prefixPath []string, s string, fields []Field) {
+	if logLevel <= l.level.Level() || logLevel <= LogLevelFatal {
 		if logLevel >= LogLevelPanic {
-			l.CdRawOutput(calldepth+1, s)
+			l.emit(calldepth+1, logLevel, prefixPath, s, joinFields(l.fields, fields))
 		}
 		if logLevel == LogLevelFatal {
 			os.Exit(1)
 		}
 		if logLevel == LogLevelPanic {
+			if len(prefixPath) > 0 {
+				s = l.prefixC + s
+			}
 			panic(s)
 		}
 	}
@@ -83,17 +141,8 @@ func (l *BasicLogger) LogStrNoPrefix(logLevel LogLevel, s string) {
 // if the given logLevel is LogLevelPanic or LogLevelFatal, exits appropriately.
 // Arguments are formatted in the style of fmt.Sprint
 func (l *BasicLogger) CdLogNoPrefix(calldepth int, logLevel LogLevel, args ...interface{}) {
-	if logLevel <= l.logLevel || logLevel <= LogLevelFatal {
-		msg := fmt.Sprint(args...)
-		if logLevel >= LogLevelPanic {
-			l.CdRawOutput(calldepth+1, msg)
-		}
-		if logLevel == LogLevelFatal {
-			os.Exit(1)
-		}
-		if logLevel == LogLevelPanic {
-			panic(msg)
-		}
+	if logLevel <= l.level.Level() || logLevel <= LogLevelFatal {
+		l.cdLogStr(calldepth+1, logLevel, nil, fmt.Sprint(args...), nil)
 	}
 }
 
@@ -108,17 +157,8 @@ func (l *BasicLogger) LogNoPrefix(logLevel LogLevel, args ...interface{}) {
 // if the given logLevel is LogLevelPanic or LogLevelFatal, exits appropriately.
 // Arguments are formatted in the style of fmt.Sprintf
 func (l *BasicLogger) CdLogfNoPrefix(calldepth int, logLevel LogLevel, f string, args ...interface{}) {
-	if logLevel <= l.logLevel || logLevel <= LogLevelFatal {
-		msg := fmt.Sprintf(f, args...)
-		if logLevel <= LogLevelPanic {
-			l.CdRawOutput(calldepth+1, msg)
-		}
-		if logLevel == LogLevelFatal {
-			os.Exit(1)
-		}
-		if logLevel == LogLevelPanic {
-			panic(msg)
-		}
+	if logLevel <= l.level.Level() || logLevel <= LogLevelFatal {
+		l.cdLogStr(calldepth+1, logLevel, nil, fmt.Sprintf(f, args...), nil)
 	}
 }
 
@@ -133,9 +173,8 @@ func (l *BasicLogger) LogfNoPrefix(logLevel LogLevel, f string, args ...interfac
 // if the given logLevel is LogLevelPanic or LogLevelFatal, exits appropriately
 // Arguments are formatted in the style of fmt.Sprint
 func (l *BasicLogger) CdLog(calldepth int, logLevel LogLevel, args ...interface{}) {
-	if logLevel <= l.logLevel || logLevel <= LogLevelFatal {
-		msg := l.Sprint(args...)
-		l.CdLogStrNoPrefix(calldepth+1, logLevel, msg)
+	if logLevel <= l.level.Level() || logLevel <= LogLevelFatal {
+		l.cdLogStr(calldepth+1, logLevel, l.prefixPath, fmt.Sprint(args...), nil)
 	}
 }
 
@@ -150,9 +189,8 @@ func (l *BasicLogger) Log(logLevel LogLevel, args ...interface{}) {
 // if the given logLevel is LogLevelPanic or LogLevelFatal, exits appropriately
 // Arguments are formatted in the style of fmt.Sprintf
 func (l *BasicLogger) CdLogf(calldepth int, logLevel LogLevel, f string, args ...interface{}) {
-	if logLevel <= l.logLevel || logLevel <= LogLevelFatal {
-		msg := l.Sprintf(f, args...)
-		l.CdLogStrNoPrefix(calldepth+1, logLevel, msg)
+	if logLevel <= l.level.Level() || logLevel <= LogLevelFatal {
+		l.cdLogStr(calldepth+1, logLevel, l.prefixPath, fmt.Sprintf(f, args...), nil)
 	}
 }
 
@@ -163,14 +201,30 @@ func (l *BasicLogger) Logf(logLevel LogLevel, f string, args ...interface{}) {
 	l.CdLogf(2, logLevel, f, args...)
 }
 
+// CdLogw outputs a message followed by structured key/value fields to a Logger with a given calldepth
+// if the given logLevel is enabled. Then, if the given logLevel is LogLevelPanic or LogLevelFatal,
+// exits appropriately. keysAndValues alternate between string keys and arbitrary values.
+func (l *BasicLogger) CdLogw(calldepth int, logLevel LogLevel, msg string, keysAndValues ...interface{}) {
+	if logLevel <= l.level.Level() || logLevel <= LogLevelFatal {
+		l.cdLogStr(calldepth+1, logLevel, l.prefixPath, msg, FieldsFromKeysAndValues(keysAndValues...))
+	}
+}
+
+// Logw outputs a message followed by structured key/value fields to a Logger if the given logLevel
+// is enabled. Then, if the given logLevel is LogLevelPanic or LogLevelFatal, exits appropriately.
+// keysAndValues alternate between string keys and arbitrary values.
+func (l *BasicLogger) Logw(logLevel LogLevel, msg string, keysAndValues ...interface{}) {
+	l.CdLogw(2, logLevel, msg, keysAndValues...)
+}
+
 // CdLogErrorf outputs an error message with a given calldepth to a Logger iff logLevel is enabled,
 // then returns an error object with a description string that has the
 // logger's prefix. If the given logLevel is LogLevelPanic or LogLevelFatal, does not return.
 // Arguments are formatted in the style of fmt.Sprintf.
 func (l *BasicLogger) CdLogErrorf(calldepth int, logLevel LogLevel, f string, args ...interface{}) error {
-	msg := l.Sprintf(f, args...)
-	l.CdLogStrNoPrefix(calldepth+1, logLevel, msg)
-	return errors.New(msg)
+	msg := fmt.Sprintf(f, args...)
+	l.cdLogStr(calldepth+1, logLevel, l.prefixPath, msg, nil)
+	return errors.New(l.prefixC + msg)
 }
 
 // LogErrorf outputs an error message to a Logger iff logLevel is enabled,
@@ -186,9 +240,9 @@ func (l *BasicLogger) LogErrorf(logLevel LogLevel, f string, args ...interface{}
 // logger's prefix. If the given logLevel is LogLevelPanic or LogLevelFatal, does not return.
 // Arguments are formatted in the style of fmt.Sprint.
 func (l *BasicLogger) CdLogError(calldepth int, logLevel LogLevel, args ...interface{}) error {
-	msg := l.Sprint(args...)
-	l.CdLogStrNoPrefix(calldepth+1, logLevel, msg)
-	return errors.New(msg)
+	msg := fmt.Sprint(args...)
+	l.cdLogStr(calldepth+1, logLevel, l.prefixPath, msg, nil)
+	return errors.New(l.prefixC + msg)
 }
 
 // LogError outputs an error message to a Logger iff logLevel is enabled,
@@ -257,6 +311,12 @@ func (l *BasicLogger) ELogf(f string, args ...interface{}) {
 	l.CdLogf(2, LogLevelError, f, args...)
 }
 
+// ELogw outputs a message followed by structured key/value fields if LogLevelError is enabled.
+// keysAndValues alternate between string keys and arbitrary values.
+func (l *BasicLogger) ELogw(msg string, keysAndValues ...interface{}) {
+	l.CdLogw(2, LogLevelError, msg, keysAndValues...)
+}
+
 // WLog outputs a formatted log message if LogLevelWarning is enabled.
 // Arguments are formatted in the style of fmt.Sprint.
 func (l *BasicLogger) WLog(args ...interface{}) {
@@ -269,6 +329,12 @@ func (l *BasicLogger) WLogf(f string, args ...interface{}) {
 	l.CdLogf(2, LogLevelWarning, f, args...)
 }
 
+// WLogw outputs a message followed by structured key/value fields if LogLevelWarning is enabled.
+// keysAndValues alternate between string keys and arbitrary values.
+func (l *BasicLogger) WLogw(msg string, keysAndValues ...interface{}) {
+	l.CdLogw(2, LogLevelWarning, msg, keysAndValues...)
+}
+
 // ILog outputs a formatted log message if LogLevelInfo is enabled.
 // Arguments are formatted in the style of fmt.Sprint.
 func (l *BasicLogger) ILog(args ...interface{}) {
@@ -281,6 +347,12 @@ func (l *BasicLogger) ILogf(f string, args ...interface{}) {
 	l.CdLogf(2, LogLevelInfo, f, args...)
 }
 
+// ILogw outputs a message followed by structured key/value fields if LogLevelInfo is enabled.
+// keysAndValues alternate between string keys and arbitrary values.
+func (l *BasicLogger) ILogw(msg string, keysAndValues ...interface{}) {
+	l.CdLogw(2, LogLevelInfo, msg, keysAndValues...)
+}
+
 // DLog outputs a formatted log message if LogLevelDebug is enabled.
 // Arguments are formatted in the style of fmt.Sprint.
 func (l *BasicLogger) DLog(args ...interface{}) {
@@ -293,6 +365,12 @@ func (l *BasicLogger) DLogf(f string, args ...interface{}) {
 	l.CdLogf(2, LogLevelDebug, f, args...)
 }
 
+// DLogw outputs a message followed by structured key/value fields if LogLevelDebug is enabled.
+// keysAndValues alternate between string keys and arbitrary values.
+func (l *BasicLogger) DLogw(msg string, keysAndValues ...interface{}) {
+	l.CdLogw(2, LogLevelDebug, msg, keysAndValues...)
+}
+
 // TLog outputs a formatted log message if LogLevelTrace is enabled.
 // Arguments are formatted in the style of fmt.Sprint.
 func (l *BasicLogger) TLog(args ...interface{}) {
@@ -305,6 +383,12 @@ func (l *BasicLogger) TLogf(f string, args ...interface{}) {
 	l.CdLogf(2, LogLevelTrace, f, args...)
 }
 
+// TLogw outputs a message followed by structured key/value fields if LogLevelTrace is enabled.
+// keysAndValues alternate between string keys and arbitrary values.
+func (l *BasicLogger) TLogw(msg string, keysAndValues ...interface{}) {
+	l.CdLogw(2, LogLevelTrace, msg, keysAndValues...)
+}
+
 // CdError generates an error object with a given calldepth and this logger's prefix.
 // Arguments are formatted in the style of fmt.Sprint.
 // Note: The raw logger's prefix, if any, is not included.
@@ -452,18 +536,23 @@ func (l *BasicLogger) TLogErrorf(f string, args ...interface{}) error {
 // ForkLogStr creates a new Logger that has an additional string appended onto
 // an existing logger's prefix (with ": " added between).
 func (l *BasicLogger) ForkLogStr(prefix string) Logger {
-	var newPrefix string
-	if prefix == "" {
-		newPrefix = l.prefix
-	} else if l.prefix == "" {
-		newPrefix = prefix
-	} else {
-		newPrefix = l.prefix + ": " + prefix
+	prefixPath := l.prefixPath
+	if prefix != "" {
+		prefixPath = make([]string, 0, len(l.prefixPath)+1)
+		prefixPath = append(prefixPath, l.prefixPath...)
+		prefixPath = append(prefixPath, prefix)
 	}
-	ll := NewLogWrapper(l.logger, newPrefix, l.GetLogLevel())
+	ll := l.fork(prefixPath, l.fields)
 	return ll
 }
 
+// fork creates a new BasicLogger with the same handler, a given prefix path and fields, and a level inherited
+// from this logger
+func (l *BasicLogger) fork(prefixPath []string, fields []Field) *BasicLogger {
+	level := l.level.newForkChild(strings.Join(prefixPath, ": "))
+	return newBasicLogger(l.handler, prefixPath, level, fields, l.sink)
+}
+
 // ForkLogf creates a new Logger that has an additional formatted string appended onto
 // an existing logger's prefix (with ": " added between).
 // Arguments are formatted in the style of fmt.Sprintf
@@ -480,18 +569,47 @@ func (l *BasicLogger) ForkLog(args ...interface{}) Logger {
 	return l.ForkLogStr(prefix)
 }
 
+// ForkWith creates a new Logger that has the same prefix as an existing logger, and additional
+// structured fields appended onto the existing logger's fields.
+// keysAndValues alternate between string keys and arbitrary values.
+func (l *BasicLogger) ForkWith(keysAndValues ...interface{}) Logger {
+	fields := joinFields(l.fields, FieldsFromKeysAndValues(keysAndValues...))
+	ll := l.fork(l.prefixPath, fields)
+	return ll
+}
+
 // Prefix returns the Logger's prefix string (does not include ": " trailer)
 // Does not include the raw logger's prefix, if any.
 func (l *BasicLogger) Prefix() string {
 	return l.prefix
 }
 
+// PrefixPath returns the individual prefix segments added by each fork of the Logger, outermost
+// first. The returned slice must not be modified.
+func (l *BasicLogger) PrefixPath() []string {
+	return l.prefixPath
+}
+
+// Fields returns the Logger's structured fields, including inherited fields. The returned
+// slice must not be modified.
+func (l *BasicLogger) Fields() []Field {
+	return l.fields
+}
+
 // GetLogLevel returns the log level
 func (l *BasicLogger) GetLogLevel() LogLevel {
-	return l.logLevel
+	return l.level.Level()
 }
 
-// SetLogLevel sets the log level
+// SetLogLevel sets the log level. The new level also applies to all loggers forked from this
+// logger (before or after the change) that have not had their own levels set. Setting LogLevelUnknown
+// causes the level to be inherited again from the logger this logger was forked from.
+// It is safe to call SetLogLevel while other goroutines are logging.
 func (l *BasicLogger) SetLogLevel(logLevel LogLevel) {
-	l.logLevel = logLevel
+	l.level.Set(logLevel)
+}
+
+// LevelVar returns the LevelVar that holds the logger's level
+func (l *BasicLogger) LevelVar() *LevelVar {
+	return l.level
 }
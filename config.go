@@ -1,18 +1,36 @@
 package logger
 
 import (
+	"fmt"
 	"io"
 	"log"
+	"os"
 )
 
 // Config provides configuration options for contruction of a Logger.  The constructed object is immutable
 // after it is constructed by NewConfig.
 type Config struct {
-	prefix       string
-	flag         int
-	logLevel     LogLevel
-	parentLogger RawLogger
-	logWriter    io.Writer
+	prefix        string
+	flag          int
+	logLevel      LogLevel
+	logLevelSet   bool
+	parentLogger  RawLogger
+	logWriter     io.Writer
+	handler       Handler
+	levelTag      LevelTag
+	format        Format
+	formatOpts    *FormatOptions
+	colorMode     ColorMode
+	levelVar      *LevelVar
+	levelRules    *LevelRules
+	specRules     []LevelRule
+	logFile       string
+	rotateOpts    *RotateOptions
+	reopenable    bool
+	reopenSignals []os.Signal
+	asyncOpts     *AsyncOptions
+	errorHandler  ErrorHandler
+	err           error
 }
 
 // ConfigOption is an opaque configuration option setter created by one of the With functions.
@@ -30,11 +48,27 @@ const (
 // can be passed to New using WithConfig, or directly to NewWithConfig.
 func NewConfig(opts ...ConfigOption) *Config {
 	cfg := &Config{
-		prefix:       "",
-		flag:         defaultLogFlags,
-		logLevel:     defaultLogLevel,
-		parentLogger: nil,
-		logWriter:    nil,
+		prefix:        "",
+		flag:          defaultLogFlags,
+		logLevel:      defaultLogLevel,
+		logLevelSet:   false,
+		parentLogger:  nil,
+		logWriter:     nil,
+		handler:       nil,
+		levelTag:      LevelTag{},
+		format:        FormatText,
+		formatOpts:    nil,
+		colorMode:     ColorAuto,
+		levelVar:      nil,
+		levelRules:    nil,
+		specRules:     nil,
+		logFile:       "",
+		rotateOpts:    nil,
+		reopenable:    false,
+		reopenSignals: nil,
+		asyncOpts:     nil,
+		errorHandler:  nil,
+		err:           nil,
 	}
 
 	for _, opt := range opts {
@@ -53,8 +87,39 @@ func WithConfig(other *Config) ConfigOption {
 		cfg.prefix = other.prefix
 		cfg.flag = other.flag
 		cfg.logLevel = other.logLevel
+		cfg.logLevelSet = other.logLevelSet
 		cfg.parentLogger = other.parentLogger
 		cfg.logWriter = other.logWriter
+		cfg.handler = other.handler
+		cfg.levelTag = other.levelTag
+		cfg.format = other.format
+		cfg.formatOpts = other.formatOpts
+		cfg.colorMode = other.colorMode
+		cfg.levelVar = other.levelVar
+		cfg.levelRules = other.levelRules
+		cfg.specRules = other.specRules
+		cfg.logFile = other.logFile
+		cfg.rotateOpts = other.rotateOpts
+		cfg.reopenable = other.reopenable
+		cfg.reopenSignals = other.reopenSignals
+		cfg.asyncOpts = other.asyncOpts
+		cfg.errorHandler = other.errorHandler
+		cfg.err = other.err
+	}
+}
+
+// Err returns an error if any of the options used to create the Config were invalid, or nil otherwise.
+// NewWithConfig fails with the same error.
+func (cfg *Config) Err() error {
+	return cfg.err
+}
+
+// addError records an error from an invalid option. Multiple errors are combined.
+func (cfg *Config) addError(err error) {
+	if cfg.err == nil {
+		cfg.err = err
+	} else {
+		cfg.err = fmt.Errorf("%s; %s", cfg.err, err)
 	}
 }
 
@@ -188,27 +253,151 @@ func WithoutLmicroseconds() ConfigOption {
 	return WithoutLogFlags(log.Lmicroseconds)
 }
 
-// WithLogLevel sets the prefix for the new logger. By default, no prefix is added.
+// WithLogLevel sets the log level for the new logger. By default, LogLevelWarning is used, unless a rule provided
+// by WithLevelRules() matches the new logger's prefix.
 func WithLogLevel(logLevel LogLevel) ConfigOption {
 	return func(cfg *Config) {
 		cfg.logLevel = logLevel
+		cfg.logLevelSet = true
+	}
+}
+
+// WithLevelTag sets how the log level of each record is rendered in text output lines. By default,
+// the level is omitted. For example, WithLevelTag(LevelTag{Style: LevelTagBracketed}) prefixes each line with
+// a tag such as "[DEBUG]". Note that the level tag is ignored if WithHandler() is provided, or if WithLogger()
+// is provided with a parent Logger that handles records itself.
+func WithLevelTag(levelTag LevelTag) ConfigOption {
+	return func(cfg *Config) {
+		cfg.levelTag = levelTag
+	}
+}
+
+// WithFormat sets the output format for the new logger. By default, FormatText is used. Unless
+// WithFormatOptions() is provided, log flags select the timestamp and caller information included in each
+// record (see TextFormatOptionsFromLogFlags and FormatOptionsFromLogFlags). Note that the format is
+// ignored if WithLogger() or WithHandler() is provided.
+func WithFormat(format Format) ConfigOption {
+	return func(cfg *Config) {
+		cfg.format = format
+	}
+}
+
+// WithFormatOptions sets the timestamp and caller options shared by all output formats, replacing those
+// derived from log flags. Note that format options are ignored if WithLogger() or WithHandler() is provided.
+func WithFormatOptions(opts FormatOptions) ConfigOption {
+	return func(cfg *Config) {
+		optsCopy := opts
+		cfg.formatOpts = &optsCopy
+	}
+}
+
+// WithColor sets whether ANSI colors are used by FormatConsole. By default, ColorAuto is used, which enables color if
+// the log writer is a terminal, subject to the NO_COLOR and FORCE_COLOR environment variables.
+func WithColor(colorMode ColorMode) ConfigOption {
+	return func(cfg *Config) {
+		cfg.colorMode = colorMode
+	}
+}
+
+// formatter creates a Formatter for the configured format and format options, for output to w
+func (cfg *Config) formatter(w io.Writer) (Formatter, error) {
+	return cfg.formatterFor(cfg.format, w)
+}
+
+// formatterFor creates a Formatter for a given format with the configured format options, for output to w
+func (cfg *Config) formatterFor(format Format, w io.Writer) (Formatter, error) {
+	var opts FormatOptions
+	if cfg.formatOpts != nil {
+		opts = *cfg.formatOpts
+	} else if format == FormatText || format == FormatConsole {
+		opts = TextFormatOptionsFromLogFlags(cfg.flag)
+	} else {
+		opts = FormatOptionsFromLogFlags(cfg.flag)
+	}
+
+	switch format {
+	case FormatText:
+		return NewTextFormatter(opts, cfg.levelTag), nil
+	case FormatJSON:
+		return NewJSONFormatter(opts), nil
+	case FormatLogfmt:
+		return NewLogfmtFormatter(opts), nil
+	case FormatConsole:
+		return NewConsoleFormatter(opts, cfg.colorMode.colorEnabled(w)), nil
+	default:
+		return nil, fmt.Errorf("Unknown log format: %d", format)
+	}
+}
+
+// WithLevelVar causes the new logger to use an existing LevelVar for its log level, so that the level
+// can be changed at runtime, or shared with other loggers, through the LevelVar. This setting overrides
+// WithLogLevel(). By default, the new logger gets a LevelVar of its own.
+func WithLevelVar(levelVar *LevelVar) ConfigOption {
+	return func(cfg *Config) {
+		cfg.levelVar = levelVar
+	}
+}
+
+// WithLevelRules causes the new logger, and all loggers forked from it, to consult a table of per-prefix
+// level rules. The table may be changed at runtime, and changes take effect on existing loggers. See LevelRules.
+// Rules matching the new logger's own prefix apply to it unless the level is also set explicitly, by WithLogLevel()
+// or a level specification (see WithLevelSpec), in which case that level is used. Rules from a level
+// specification take precedence over the table. This setting is ignored if WithLevelVar() is provided. By
+// default, no rules are consulted.
+func WithLevelRules(levelRules *LevelRules) ConfigOption {
+	return func(cfg *Config) {
+		cfg.levelRules = levelRules
 	}
 }
 
 // WithWriter sets the io,Writer to which log output will be sent. By default, log output will be sent to stderr.
-// This setting replaces any prior effect of WithLogger().
+// This setting replaces any prior effect of WithLogger(), WithHandler(), WithLogFile(), WithRotatingFile() or
+// WithReopenableLogFile().
 func WithWriter(logWriter io.Writer) ConfigOption {
 	return func(cfg *Config) {
 		cfg.logWriter = logWriter
 		cfg.parentLogger = nil
+		cfg.handler = nil
+		cfg.logFile = ""
+	}
+}
+
+// WithLogFile causes log output to be appended to a file, which is created if it does not exist. The file is
+// opened by NewWithConfig, and remains open for the life of the process. By default, log output will be sent to
+// stderr. This setting replaces any prior effect of WithWriter(), WithLogger(), WithHandler(), WithRotatingFile() or
+// WithReopenableLogFile().
+func WithLogFile(path string) ConfigOption {
+	return func(cfg *Config) {
+		cfg.logFile = path
+		cfg.rotateOpts = nil
+		cfg.reopenable = false
+		cfg.logWriter = nil
+		cfg.parentLogger = nil
+		cfg.handler = nil
 	}
 }
 
 // WithLogger sets the parent logger for new logger. By default, a new logger to stderr
-// is created. This setting replaces any prior effect of WithWriter().
+// is created. This setting replaces any prior effect of WithWriter(), WithHandler(), WithLogFile(), WithRotatingFile() or
+// WithReopenableLogFile().
 func WithLogger(parentLogger RawLogger) ConfigOption {
 	return func(cfg *Config) {
 		cfg.parentLogger = parentLogger
 		cfg.logWriter = nil
+		cfg.handler = nil
+		cfg.logFile = ""
+	}
+}
+
+// WithHandler sets the Handler that will receive structured log Records from the new logger. By default,
+// records are rendered as text to a new logger to stderr. Note that log flags are ignored if WithHandler()
+// is provided. This setting replaces any prior effect of WithWriter(), WithLogger(), WithLogFile(), WithRotatingFile() or
+// WithReopenableLogFile().
+func WithHandler(handler Handler) ConfigOption {
+	return func(cfg *Config) {
+		cfg.handler = handler
+		cfg.parentLogger = nil
+		cfg.logWriter = nil
+		cfg.logFile = ""
 	}
 }
@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ColorMode selects whether ANSI color escape sequences are used by the console output format
+type ColorMode int
+
+const (
+	// ColorAuto enables color if the FORCE_COLOR environment variable is set to a value other than "0" or
+	// "false", or if the NO_COLOR environment variable is not set and the output writer is a terminal.
+	// This is the default.
+	ColorAuto ColorMode = iota
+
+	// ColorAlways enables color regardless of the environment or output writer
+	ColorAlways ColorMode = iota
+
+	// ColorNever disables color regardless of the environment or output writer
+	ColorNever ColorMode = iota
+)
+
+// ANSI escape sequences used by ConsoleFormatter
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiBlue    = "\x1b[34m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+	ansiBoldRed = "\x1b[1;31m"
+)
+
+// consoleLevelNames are the fixed-width level names used by ConsoleFormatter
+var consoleLevelNames = [...]string{
+	"???", "PNC", "FTL", "ERR", "WRN", "INF", "DBG", "TRC",
+}
+
+// consoleLevelColors are the colors of the level names used by ConsoleFormatter
+var consoleLevelColors = [...]string{
+	"", ansiBoldRed, ansiBoldRed, ansiRed, ansiYellow, ansiGreen, ansiBlue, ansiMagenta,
+}
+
+// ConsoleFormatter is a Formatter that renders each Record as a line intended for a human reading a
+// terminal: a dimmed timestamp, a fixed-width colored level, the fork prefix chain highlighted,
+// the message, and the fields with dimmed keys. Color is only used if Color is true.
+type ConsoleFormatter struct {
+	FormatOptions
+
+	// Color enables ANSI color escape sequences
+	Color bool
+}
+
+// NewConsoleFormatter creates a ConsoleFormatter with the given options
+func NewConsoleFormatter(opts FormatOptions, color bool) *ConsoleFormatter {
+	return &ConsoleFormatter{FormatOptions: opts, Color: color}
+}
+
+// Format appends the console rendering of a Record, including a trailing newline, to b
+func (f *ConsoleFormatter) Format(b []byte, r *Record) []byte {
+	if f.TimeLayout != "" {
+		b = f.appendStyled(b, ansiDim, string(f.appendTime(nil, r.Time)))
+		b = append(b, ' ')
+	}
+	if r.Level != LogLevelUnknown {
+		level := r.Level
+		if level < LogLevelUnknown || level > LogLevelTrace {
+			level = LogLevelUnknown
+		}
+		b = f.appendStyled(b, consoleLevelColors[level], consoleLevelNames[level])
+		b = append(b, ' ')
+	}
+	caller := f.caller(r.PC)
+	if caller != "" {
+		b = f.appendStyled(b, ansiDim, caller)
+		b = append(b, ' ')
+	}
+	for _, segment := range r.PrefixPath {
+		b = f.appendStyled(b, ansiBold+ansiCyan, segment)
+		b = f.appendStyled(b, ansiDim, ":")
+		b = append(b, ' ')
+	}
+	b = append(b, strings.TrimSuffix(r.Message, "\n")...)
+	for _, field := range r.Fields {
+		b = append(b, ' ')
+		b = f.appendStyled(b, ansiDim, string(appendMaybeQuoted(nil, field.Key))+"=")
+		b = appendMaybeQuoted(b, fieldValueString(field.Value))
+	}
+	return append(b, '\n')
+}
+
+// appendStyled appends s to b, wrapped in the given ANSI style if color is enabled
+func (f *ConsoleFormatter) appendStyled(b []byte, style string, s string) []byte {
+	if !f.Color || style == "" {
+		return append(b, s...)
+	}
+	b = append(b, style...)
+	b = append(b, s...)
+	return append(b, ansiReset...)
+}
+
+// colorEnabled determines whether color should be used for output to w
+func (mode ColorMode) colorEnabled(w io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	force, ok := os.LookupEnv("FORCE_COLOR")
+	if ok {
+		return force != "0" && !strings.EqualFold(force, "false")
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// isTerminal returns true if w is an *os.File that refers to a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	return isTerminalFile(f)
+}
+
+var colorModeNames = [...]string{
+	"auto", "always", "never",
+}
+
+// String converts a ColorMode to a string (lowercase)
+func (mode ColorMode) String() string {
+	if mode < 0 || int(mode) >= len(colorModeNames) {
+		return "unknown"
+	}
+	return colorModeNames[mode]
+}
+
+// StringToColorMode converts a color mode name to a ColorMode. Upper/lowercase is accepted.
+func StringToColorMode(s string) (ColorMode, error) {
+	ls := strings.ToLower(s)
+	for i, name := range colorModeNames {
+		if name == ls {
+			return ColorMode(i), nil
+		}
+	}
+	return ColorAuto, fmt.Errorf("Unknown color mode: \"%s\"", s)
+}
+
+// Set initializes a ColorMode from a string. Upper/lowercase are accepted. Together with
+// String and Type, this allows a ColorMode to be used as a flag.Value or pflag.Value.
+func (mode *ColorMode) Set(s string) error {
+	colorMode, err := StringToColorMode(s)
+	if err != nil {
+		return err
+	}
+	*mode = colorMode
+	return nil
+}
+
+// Type returns the name of the ColorMode type, for use in flag usage messages
+func (mode *ColorMode) Type() string {
+	return "mode"
+}
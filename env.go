@@ -0,0 +1,182 @@
+package logger
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// Environment variable name suffixes read by WithEnv
+const (
+	envLogLevel  = "LOG_LEVEL"
+	envLogFormat = "LOG_FORMAT"
+	envLogFlags  = "LOG_FLAGS"
+	envLogSpec   = "LOG_SPEC"
+)
+
+// logFlagNames maps the names accepted by ParseLogFlags to log flag bits
+var logFlagNames = map[string]int{
+	"date":         log.Ldate,
+	"time":         log.Ltime,
+	"micro":        log.Lmicroseconds,
+	"microseconds": log.Lmicroseconds,
+	"longfile":     log.Llongfile,
+	"shortfile":    log.Lshortfile,
+	"utc":          log.LUTC,
+	"msgprefix":    log.Lmsgprefix,
+	"std":          log.LstdFlags,
+}
+
+// ParseLogFlags applies a comma-separated list of log flag names to an initial set of log flags, and returns
+// the result. Recognized names are "date", "time", "micro" (or "microseconds"), "longfile", "shortfile",
+// "utc", "msgprefix" and "std" (date and time). A name prefixed with "no" or "-" clears the flag rather
+// than setting it, and "none" clears all flags. Upper/lowercase is accepted.
+func ParseLogFlags(s string, flag int) (int, error) {
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if name == "none" {
+			flag = 0
+			continue
+		}
+		clear := false
+		if strings.HasPrefix(name, "-") {
+			clear = true
+			name = name[1:]
+		} else if _, ok := logFlagNames[name]; !ok && strings.HasPrefix(name, "no") {
+			clear = true
+			name = name[2:]
+		}
+		bits, ok := logFlagNames[name]
+		if !ok {
+			return flag, fmt.Errorf("Unknown log flag: \"%s\"", name)
+		}
+		if clear {
+			flag &= ^bits
+		} else {
+			flag |= bits
+		}
+	}
+	return flag, nil
+}
+
+// ParseLevelSpec parses a comma-separated level specification in the style of GODEBUG, such as
+// "info,db=debug,http=warning". An entry without '=' sets the default level, which is returned as
+// LogLevelUnknown if there is no such entry. Each pattern=level entry is returned as a LevelRule;
+// see LevelRule for the pattern syntax.
+func ParseLevelSpec(spec string) (LogLevel, []LevelRule, error) {
+	defaultLevel := LogLevelUnknown
+	var rules []LevelRule
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		i := strings.LastIndex(entry, "=")
+		if i < 0 {
+			err := defaultLevel.FromString(entry)
+			if err != nil {
+				return LogLevelUnknown, nil, err
+			}
+			continue
+		}
+		var logLevel LogLevel
+		err := logLevel.FromString(strings.TrimSpace(entry[i+1:]))
+		if err != nil {
+			return LogLevelUnknown, nil, err
+		}
+		rules = append(rules, LevelRule{Pattern: strings.TrimSpace(entry[:i]), Level: logLevel})
+	}
+	return defaultLevel, rules, nil
+}
+
+// WithEnv configures the new logger from environment variables whose names begin with a given prefix
+// followed by "_" (or no prefix, if the prefix is empty). For example, with prefix "MYAPP":
+//
+//	MYAPP_LOG_LEVEL=debug                      sets the log level (see WithLogLevel)
+//	MYAPP_LOG_FORMAT=json                      sets the output format (see WithFormat)
+//	MYAPP_LOG_FLAGS=utc,micro,shortfile        adjusts the log flags (see ParseLogFlags)
+//	MYAPP_LOG_SPEC=info,db=debug,http=warning  sets the log level and per-prefix level rules (see ParseLevelSpec)
+//
+// Unset or empty variables leave the configuration unchanged. Rules from the spec take precedence over the
+// LevelRules table provided by WithLevelRules(), whether it is provided before or after WithEnv, and the table
+// itself is unchanged. Other options that follow WithEnv override the environment. Parse errors are returned
+// by NewWithConfig.
+func WithEnv(prefix string) ConfigOption {
+	return func(cfg *Config) {
+		cfg.applyEnv(prefix, os.Getenv)
+	}
+}
+
+// applyEnv configures cfg from environment variables with a given prefix, looked up with getenv
+func (cfg *Config) applyEnv(prefix string, getenv func(string) string) {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	if s := getenv(prefix + envLogLevel); s != "" {
+		err := cfg.logLevel.FromString(s)
+		if err != nil {
+			cfg.addError(fmt.Errorf("%s%s: %s", prefix, envLogLevel, err))
+		} else {
+			cfg.logLevelSet = true
+		}
+	}
+
+	if s := getenv(prefix + envLogFormat); s != "" {
+		format, err := StringToFormat(s)
+		if err != nil {
+			cfg.addError(fmt.Errorf("%s%s: %s", prefix, envLogFormat, err))
+		} else {
+			cfg.format = format
+		}
+	}
+
+	if s := getenv(prefix + envLogFlags); s != "" {
+		flag, err := ParseLogFlags(s, cfg.flag)
+		if err != nil {
+			cfg.addError(fmt.Errorf("%s%s: %s", prefix, envLogFlags, err))
+		} else {
+			cfg.flag = flag
+		}
+	}
+
+	if s := getenv(prefix + envLogSpec); s != "" {
+		err := cfg.applyLevelSpec(s)
+		if err != nil {
+			cfg.addError(fmt.Errorf("%s%s: %s", prefix, envLogSpec, err))
+		}
+	}
+}
+
+// WithLevelSpec sets the log level and adds per-prefix level rules from a level specification such as
+// "info,db=debug,http=warning" (see ParseLevelSpec). The rules take precedence over the LevelRules table
+// provided by WithLevelRules(), regardless of option order, and the table itself is unchanged, so later
+// changes to it still take effect. Parse errors are returned by NewWithConfig.
+func WithLevelSpec(spec string) ConfigOption {
+	return func(cfg *Config) {
+		err := cfg.applyLevelSpec(spec)
+		if err != nil {
+			cfg.addError(err)
+		}
+	}
+}
+
+// applyLevelSpec sets the log level and adds per-prefix level rules from a level specification
+func (cfg *Config) applyLevelSpec(spec string) error {
+	defaultLevel, rules, err := ParseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+	if defaultLevel != LogLevelUnknown {
+		cfg.logLevel = defaultLevel
+		cfg.logLevelSet = true
+	}
+	// The rules are kept apart from the table provided by WithLevelRules(), which may be shared and changed
+	// at runtime. The slice is copied on append, since it may be shared with another Config by WithConfig().
+	cfg.specRules = append(cfg.specRules[:len(cfg.specRules):len(cfg.specRules)], rules...)
+	return nil
+}
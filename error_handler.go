@@ -0,0 +1,199 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrorHandler is called by a BasicLogger when its Handler fails to handle a Record, for example because the
+// disk is full or the output pipe has been closed. It is passed the Handler, the Record and the error, and may
+// handle the record again or deliver it elsewhere. It returns nil if the record was delivered, or an error
+// otherwise. An ErrorHandler is called on the logging goroutine, and may be called concurrently.
+//
+// The Record's CallDepth is adjusted so that it is correct if the ErrorHandler passes the Record directly to
+// the Handler's Handle method. See WithErrorHandler.
+type ErrorHandler func(handler Handler, r *Record, err error) error
+
+// FallbackErrorHandler returns an ErrorHandler that writes records that could not be handled to an io.Writer,
+// as a line describing the error followed by the record as a line of text. If w is nil, os.Stderr is used.
+func FallbackErrorHandler(w io.Writer) ErrorHandler {
+	if w == nil {
+		w = os.Stderr
+	}
+	formatter := NewTextFormatter(TextFormatOptionsFromLogFlags(log.LstdFlags), LevelTag{Style: LevelTagBracketed})
+	var mu sync.Mutex
+	return func(handler Handler, r *Record, err error) error {
+		b := fmt.Appendf(nil, "logger: unable to write log record: %s\n", err)
+		b = formatter.Format(b, r)
+		mu.Lock()
+		defer mu.Unlock()
+		_, werr := w.Write(b)
+		return werr
+	}
+}
+
+// RetryErrorHandler returns an ErrorHandler that passes records that could not be handled to the Handler again,
+// up to a number of attempts, waiting for a backoff interval before the first attempt and doubling it before
+// each subsequent attempt. If every attempt fails, the record is passed to next, if it is not nil. Since the
+// logging goroutine waits while retrying, this is best combined with WithAsync. Note that a Handler that
+// delivers a record to several destinations, such as a TeeHandler, may deliver it again to the destinations
+// that succeeded.
+func RetryErrorHandler(attempts int, backoff time.Duration, next ErrorHandler) ErrorHandler {
+	return func(handler Handler, r *Record, err error) error {
+		delay := backoff
+		for i := 0; i < attempts; i++ {
+			time.Sleep(delay)
+			delay *= 2
+			err = handler.Handle(r)
+			if err == nil {
+				return nil
+			}
+		}
+		if next != nil {
+			// One frame deeper than the caller of this ErrorHandler
+			rr := *r
+			rr.CallDepth++
+			return next(handler, &rr, err)
+		}
+		return err
+	}
+}
+
+// LogStats contains statistics about the records that a BasicLogger, and all of the loggers forked from it,
+// have passed to their Handler
+type LogStats struct {
+	// Records is the number of records passed to the Handler
+	Records uint64
+
+	// Failures is the number of records that the Handler failed to handle
+	Failures uint64
+
+	// Lost is the number of records that the Handler failed to handle and that were not delivered by the
+	// ErrorHandler, if any
+	Lost uint64
+
+	// Dropped is the number of records that were dropped because the queue was full, if the Handler is an
+	// AsyncHandler (see WithAsync)
+	Dropped uint64
+
+	// BackgroundErrors is the number of errors reported in the background by a log file opened by the logger;
+	// e.g., failures to compress a rotated segment, or to reopen a ReopenableFile on a signal
+	BackgroundErrors uint64
+
+	// LastError is the most recent error returned by the Handler, or reported in the background by the log file
+	// (e.g., a failure to compress a rotated segment), or nil if there has been none
+	LastError error
+
+	// LastErrorTime is the time of LastError
+	LastErrorTime time.Time
+}
+
+// sinkState holds the ErrorHandler and statistics shared by a BasicLogger and all of the loggers forked from it
+type sinkState struct {
+	errorHandler ErrorHandler
+	// handler is the Handler whose dropped records are counted if it is an AsyncHandler. It is set before the
+	// sinkState is shared.
+	handler          Handler
+	records          atomic.Uint64
+	failures         atomic.Uint64
+	lost             atomic.Uint64
+	backgroundErrors atomic.Uint64
+
+	mu            sync.Mutex
+	lastErr       error
+	lastErrorTime time.Time
+}
+
+// newSinkState creates a sinkState with an optional ErrorHandler
+func newSinkState(errorHandler ErrorHandler) *sinkState {
+	return &sinkState{errorHandler: errorHandler}
+}
+
+// done records the result of passing a Record to a Handler, calls the ErrorHandler if it failed, and returns
+// the final error
+func (s *sinkState) done(handler Handler, r *Record, err error) error {
+	s.records.Add(1)
+	if err == nil {
+		return nil
+	}
+	// The ErrorHandler's call to Handle is three frames deeper than the caller of done
+	return s.failed(handler, r, err, 3)
+}
+
+// failed records that a Handler failed to handle a Record, calls the ErrorHandler with the Record's CallDepth
+// increased by depth, and returns the final error. It is also used as the ErrorHandler of an AsyncHandler, for
+// records that were counted when they were queued.
+func (s *sinkState) failed(handler Handler, r *Record, err error, depth int) error {
+	s.failures.Add(1)
+	s.setLastError(err)
+	if s.errorHandler != nil {
+		rr := *r
+		rr.CallDepth += depth
+		err = s.errorHandler(handler, &rr, err)
+	}
+	if err != nil {
+		s.lost.Add(1)
+	}
+	return err
+}
+
+// asyncFailed is the ErrorHandler of an AsyncHandler created by WithAsync
+func (s *sinkState) asyncFailed(handler Handler, r *Record, err error) error {
+	return s.failed(handler, r, err, 0)
+}
+
+// backgroundError records an error that occurred outside of a call to the Handler, such as a failure to compress
+// a rotated log file. It is not passed to the ErrorHandler, which is for records that could not be handled.
+func (s *sinkState) backgroundError(err error) {
+	s.backgroundErrors.Add(1)
+	s.setLastError(err)
+}
+
+// setLastError sets the most recent error
+func (s *sinkState) setLastError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastErr = err
+	s.lastErrorTime = time.Now()
+}
+
+// stats returns a snapshot of the statistics
+func (s *sinkState) stats() LogStats {
+	var dropped uint64
+	if ah, ok := s.handler.(*AsyncHandler); ok {
+		dropped = ah.Dropped()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return LogStats{
+		Records:          s.records.Load(),
+		Failures:         s.failures.Load(),
+		Lost:             s.lost.Load(),
+		Dropped:          dropped,
+		BackgroundErrors: s.backgroundErrors.Load(),
+		LastError:        s.lastErr,
+		LastErrorTime:    s.lastErrorTime,
+	}
+}
+
+// Stats returns statistics about the records passed to the logger's Handler by the logger and all loggers
+// forked from the same root logger, so that a health check can report logging failures
+func (l *BasicLogger) Stats() LogStats {
+	return l.sink.stats()
+}
+
+// WithErrorHandler sets the ErrorHandler that is called when the new logger's Handler fails to handle a
+// record; e.g., FallbackErrorHandler(nil) or RetryErrorHandler(3, 10*time.Millisecond, FallbackErrorHandler(nil)).
+// By default, failures are only counted; see BasicLogger.Stats. Errors reported in the background by a log file
+// opened by the new logger, such as a failure to compress a rotated segment, are not passed to the ErrorHandler;
+// they are counted in BasicLogger.Stats.
+func WithErrorHandler(errorHandler ErrorHandler) ConfigOption {
+	return func(cfg *Config) {
+		cfg.errorHandler = errorHandler
+	}
+}
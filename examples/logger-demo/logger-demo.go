@@ -6,7 +6,6 @@ import (
 
 	"github.com/sammck-go/logger"
 	flag "github.com/spf13/pflag"
-	"github.com/thediveo/enumflag"
 )
 
 type testObj struct {
@@ -29,61 +28,16 @@ const (
 	timeLen = 8
 )
 
-// LogLevelVarP adds a custom pflag that can be set to one of the known LogLevels by name
-func LogLevelVarP(logLevel *logger.LogLevel, name string, shorthand string, value logger.LogLevel, usage string) {
-	*logLevel = value
-	logLevelOpts := map[logger.LogLevel][]string{}
-	for ll, s := range logger.LogLevelToName {
-		if s != "unknown" {
-			logLevelOpts[ll] = []string{s}
-		}
-	}
-	flag.VarP(
-		enumflag.New(logLevel, "LogLevel", logLevelOpts, enumflag.EnumCaseInsensitive),
-		name,
-		shorthand,
-		usage,
-	)
-}
-
 func run() error {
-
-	noDate := false
-	noTime := false
-	withLongFile := false
-	withShortFile := false
-	withMicroseconds := false
-	withUTC := false
-	logLevel := logger.LogLevelUnknown
-	flag.BoolVarP(&noDate, "no-date", "", false, "Do not include a date in the timestamp.")
-	flag.BoolVarP(&noTime, "no-time", "", false, "Do not include a time of date in the timestamp.")
-	flag.BoolVarP(&withLongFile, "with-long-file", "", false, "include long filename and line number.")
-	flag.BoolVarP(&withShortFile, "with-short-file", "", false, "include short filename and line number.")
-	flag.BoolVarP(&withMicroseconds, "microseconds", "", false, "Display time with microsecond resolution.")
-	flag.BoolVarP(&withUTC, "utc", "u", false, "Display date/time as UTC.")
-	LogLevelVarP(&logLevel, "loglevel", "", logger.LogLevelDebug, "Set the log level.")
+	cfg := logger.NewConfig(
+		logger.WithLogLevel(logger.LogLevelDebug),
+		logger.WithFormat(logger.FormatConsole),
+	)
+	logFlags := logger.RegisterFlagsWithDefaults(flag.CommandLine, "", cfg)
 
 	flag.Parse()
 
-	cfg := logger.NewConfig(logger.WithLogLevel(logLevel))
-	if withShortFile {
-		cfg = cfg.Refine(logger.WithLshortfile())
-	}
-	if withLongFile {
-		cfg = cfg.Refine(logger.WithLlongfile())
-	}
-	if withUTC {
-		cfg = cfg.Refine(logger.WithLUTC())
-	}
-	if withMicroseconds {
-		cfg = cfg.Refine(logger.WithLmicroseconds())
-	}
-	if noDate {
-		cfg = cfg.Refine(logger.WithoutLdate())
-	}
-	if noTime {
-		cfg = cfg.Refine(logger.WithoutLtime())
-	}
+	cfg = cfg.Refine(logger.WithConfigFlags(logFlags))
 
 	lg, err := logger.New(logger.WithConfig(cfg))
 
@@ -109,6 +63,10 @@ func run() error {
 		}
 	}
 
+	tos[1].ILogw("Structured message", "counter", tos[1].counter)
+	tos[2].WLogf("Warning message")
+	tos[3].ELogf("Error message")
+
 	return nil
 }
 
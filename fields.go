@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Field is a single structured key/value pair attached to a log entry. Fields attached to a Logger
+// with ForkWith are inherited by all loggers forked from it, in the same way that prefixes are.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// badKey is the key used for a value in a key/value list that is not preceded by a string key
+const badKey = "!BADKEY"
+
+// F creates a Field from a key and a value. It is a convenience for callers that prefer
+// explicitly typed fields to alternating keys and values.
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// String formats the field as key=value, quoting the value if necessary
+func (f Field) String() string {
+	return string(appendField(nil, f))
+}
+
+// FieldsFromKeysAndValues converts an alternating list of keys and values into a slice of Fields.
+// Each key must be a string; an element that is already a Field is taken as-is. A value that is
+// not preceded by a string key is given the key "!BADKEY".
+func FieldsFromKeysAndValues(keysAndValues ...interface{}) []Field {
+	if len(keysAndValues) == 0 {
+		return nil
+	}
+	fields := make([]Field, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i++ {
+		switch x := keysAndValues[i].(type) {
+		case Field:
+			fields = append(fields, x)
+		case []Field:
+			fields = append(fields, x...)
+		case string:
+			if i+1 < len(keysAndValues) {
+				fields = append(fields, Field{Key: x, Value: keysAndValues[i+1]})
+				i++
+			} else {
+				fields = append(fields, Field{Key: badKey, Value: x})
+			}
+		default:
+			fields = append(fields, Field{Key: badKey, Value: x})
+		}
+	}
+	return fields
+}
+
+// joinFields returns a new slice containing a followed by b. If either slice is empty, the other
+// is returned without copying; callers must treat field slices as immutable.
+func joinFields(a []Field, b []Field) []Field {
+	if len(b) == 0 {
+		return a
+	}
+	if len(a) == 0 {
+		return b
+	}
+	result := make([]Field, 0, len(a)+len(b))
+	result = append(result, a...)
+	return append(result, b...)
+}
+
+// appendFields appends fields to b in the form " key=value key2=value2"
+func appendFields(b []byte, fields []Field) []byte {
+	for _, f := range fields {
+		b = append(b, ' ')
+		b = appendField(b, f)
+	}
+	return b
+}
+
+// appendField appends a single field to b in the form key=value
+func appendField(b []byte, f Field) []byte {
+	b = appendMaybeQuoted(b, f.Key)
+	b = append(b, '=')
+	return appendMaybeQuoted(b, fieldValueString(f.Value))
+}
+
+// fieldValueString renders a field value as a string
+func fieldValueString(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "<nil>"
+	case string:
+		return x
+	case error:
+		return callStringMethod(v, "Error", x.Error)
+	case fmt.Stringer:
+		return callStringMethod(v, "String", x.String)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// callStringMethod calls the Error or String method of v, recovering from a panic in the same way
+// as fmt: a nil pointer receiver is rendered as "<nil>", and any other panic is described in the
+// returned string
+func callStringMethod(v interface{}, method string, f func() string) (s string) {
+	defer func() {
+		if err := recover(); err != nil {
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+				s = "<nil>"
+				return
+			}
+			s = fmt.Sprintf("%%!v(PANIC=%s method: %v)", method, err)
+		}
+	}()
+	return f()
+}
+
+// appendMaybeQuoted appends s to b, quoting it if it is empty or contains spaces, quotes, '=' or
+// non-printable characters
+func appendMaybeQuoted(b []byte, s string) []byte {
+	if needsQuoting(s) {
+		return strconv.AppendQuote(b, s)
+	}
+	return append(b, s...)
+}
+
+// needsQuoting returns true if s must be quoted to be unambiguously parsed as a key or value
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c <= ' ' || c == '=' || c == '"' || c == '\\' || c == 0x7f {
+				return true
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+		i += size
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// ConfigFlags holds the values of the command line flags added by RegisterFlags. After the flags have
+// been parsed, Options returns the corresponding ConfigOptions.
+type ConfigFlags struct {
+	fs     *pflag.FlagSet
+	prefix string
+
+	// LogLevel is the value of --log-level
+	LogLevel LogLevel
+	// Format is the value of --log-format
+	Format Format
+	// Color is the value of --log-color
+	Color ColorMode
+	// File is the value of --log-file
+	File string
+	// Spec is the value of --log-spec
+	Spec string
+	// NoDate is the value of --log-no-date
+	NoDate bool
+	// NoTime is the value of --log-no-time
+	NoTime bool
+	// Microseconds is the value of --log-microseconds
+	Microseconds bool
+	// UTC is the value of --log-utc
+	UTC bool
+	// ShortFile is the value of --log-short-file
+	ShortFile bool
+	// LongFile is the value of --log-long-file
+	LongFile bool
+}
+
+// RegisterFlags adds command line flags that configure a Logger to a pflag.FlagSet, and returns a ConfigFlags
+// that holds their values. Each flag name is prefixed with prefix, which may be empty; e.g., with prefix "db-",
+// the level flag is --db-log-level. The flags are:
+//
+//	--log-level         the log level (panic, fatal, error, warning, info, debug or trace)
+//	--log-format        the output format (text, json, logfmt or console)
+//	--log-color         use colors in console output (auto, always or never)
+//	--log-file          append log output to a file rather than stderr
+//	--log-spec          a level specification with per-prefix rules, e.g. "info,db=debug" (see ParseLevelSpec)
+//	--log-no-date       do not include a date in the timestamp
+//	--log-no-time       do not include a time of day in the timestamp
+//	--log-microseconds  include microseconds in the timestamp
+//	--log-utc           display the timestamp in UTC
+//	--log-short-file    include the short source file name and line number
+//	--log-long-file     include the full source file name and line number
+func RegisterFlags(fs *pflag.FlagSet, prefix string) *ConfigFlags {
+	return RegisterFlagsWithDefaults(fs, prefix, NewConfig())
+}
+
+// RegisterFlagsWithDefaults is the same as RegisterFlags, except that the default values of the flags shown in
+// usage messages are taken from a Config. The Config should be the one that the flag options are applied to.
+func RegisterFlagsWithDefaults(fs *pflag.FlagSet, prefix string, defaults *Config) *ConfigFlags {
+	cf := &ConfigFlags{
+		fs:       fs,
+		prefix:   prefix,
+		LogLevel: defaults.logLevel,
+		Format:   defaults.format,
+		Color:    defaults.colorMode,
+	}
+
+	fs.Var(&cf.LogLevel, prefix+"log-level", "Set the log level (panic, fatal, error, warning, info, debug or trace).")
+	fs.Var(&cf.Format, prefix+"log-format", "Set the log output format (text, json, logfmt or console).")
+	fs.Var(&cf.Color, prefix+"log-color", "Use colors in console log output (auto, always or never).")
+	fs.StringVar(&cf.File, prefix+"log-file", "", "Append log output to a file rather than stderr.")
+	fs.StringVar(&cf.Spec, prefix+"log-spec", "", "Set the log level and per-prefix levels, e.g. \"info,db=debug\".")
+	fs.BoolVar(&cf.NoDate, prefix+"log-no-date", false, "Do not include a date in the log timestamp.")
+	fs.BoolVar(&cf.NoTime, prefix+"log-no-time", false, "Do not include a time of day in the log timestamp.")
+	fs.BoolVar(&cf.Microseconds, prefix+"log-microseconds", false, "Include microseconds in the log timestamp.")
+	fs.BoolVar(&cf.UTC, prefix+"log-utc", false, "Display the log timestamp in UTC.")
+	fs.BoolVar(&cf.ShortFile, prefix+"log-short-file", false, "Include the short source file name and line number in log output.")
+	fs.BoolVar(&cf.LongFile, prefix+"log-long-file", false, "Include the full source file name and line number in log output.")
+
+	return cf
+}
+
+// changed returns true if the flag with the given unprefixed name was set on the command line
+func (cf *ConfigFlags) changed(name string) bool {
+	return cf.fs.Changed(cf.prefix + name)
+}
+
+// Options returns ConfigOptions for the flags that were set on the command line. Flags that were not set
+// produce no options, so the result can be layered over other options such as WithEnv:
+//
+//	lg, err := logger.New(logger.WithEnv("MYAPP"), logger.WithConfigFlags(cf))
+func (cf *ConfigFlags) Options() []ConfigOption {
+	opts := []ConfigOption{}
+	if cf.changed("log-level") {
+		opts = append(opts, WithLogLevel(cf.LogLevel))
+	}
+	if cf.changed("log-spec") {
+		opts = append(opts, WithLevelSpec(cf.Spec))
+	}
+	if cf.changed("log-format") {
+		opts = append(opts, WithFormat(cf.Format))
+	}
+	if cf.changed("log-color") {
+		opts = append(opts, WithColor(cf.Color))
+	}
+	if cf.changed("log-file") {
+		opts = append(opts, WithLogFile(cf.File))
+	}
+	if cf.NoDate {
+		opts = append(opts, WithoutLdate())
+	}
+	if cf.NoTime {
+		opts = append(opts, WithoutLtime())
+	}
+	if cf.Microseconds {
+		opts = append(opts, WithLmicroseconds())
+	}
+	if cf.UTC {
+		opts = append(opts, WithLUTC())
+	}
+	if cf.ShortFile {
+		opts = append(opts, WithLshortfile())
+	}
+	if cf.LongFile {
+		opts = append(opts, WithLlongfile())
+	}
+	return opts
+}
+
+// WithConfigFlags applies the ConfigOptions for the command line flags in a ConfigFlags that were set
+// on the command line. See ConfigFlags.Options.
+func WithConfigFlags(cf *ConfigFlags) ConfigOption {
+	return func(cfg *Config) {
+		for _, opt := range cf.Options() {
+			opt(cfg)
+		}
+	}
+}
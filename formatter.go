@@ -0,0 +1,222 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Format selects the output format used by a Logger created with New or NewWithConfig
+type Format int
+
+const (
+	// FormatText renders each record as a traditional text line, with a timestamp and caller
+	// added according to the log flags in the style of log.Logger. This is the default.
+	FormatText Format = iota
+
+	// FormatJSON renders each record as a single-line JSON object (JSON lines)
+	FormatJSON Format = iota
+
+	// FormatLogfmt renders each record as a single line of logfmt key=value pairs
+	FormatLogfmt Format = iota
+
+	// FormatConsole renders each record as a line intended for a human reading a terminal, optionally
+	// with ANSI colors
+	FormatConsole Format = iota
+)
+
+var formatNames = [...]string{
+	"text", "json", "logfmt", "console",
+}
+
+// String converts a Format to a string (lowercase)
+func (f Format) String() string {
+	if f < 0 || int(f) >= len(formatNames) {
+		return "unknown"
+	}
+	return formatNames[f]
+}
+
+// StringToFormat converts a format name to a Format. Upper/lowercase is accepted.
+func StringToFormat(s string) (Format, error) {
+	ls := strings.ToLower(s)
+	for i, name := range formatNames {
+		if name == ls {
+			return Format(i), nil
+		}
+	}
+	return FormatText, fmt.Errorf("Unknown log format: \"%s\"", s)
+}
+
+// Set initializes a Format from a string. Upper/lowercase are accepted. Together with
+// String and Type, this allows a Format to be used as a flag.Value or pflag.Value.
+func (f *Format) Set(s string) error {
+	format, err := StringToFormat(s)
+	if err != nil {
+		return err
+	}
+	*f = format
+	return nil
+}
+
+// Type returns the name of the Format type, for use in flag usage messages
+func (f *Format) Type() string {
+	return "format"
+}
+
+// Formatter is an interface for a component that renders a Record as a sequence of bytes
+type Formatter interface {
+	// Format appends the rendering of a Record, including a trailing newline, to b and returns the
+	// extended buffer.
+	Format(b []byte, r *Record) []byte
+}
+
+// CallerStyle selects how the caller's source location is rendered by a Formatter
+type CallerStyle int
+
+const (
+	// CallerNone omits the caller's source location
+	CallerNone CallerStyle = iota
+
+	// CallerShort renders the caller's source location as the final file name element and line number, e.g., "d.go:23"
+	CallerShort CallerStyle = iota
+
+	// CallerLong renders the caller's source location as the full file name and line number, e.g., "/a/b/c/d.go:23"
+	CallerLong CallerStyle = iota
+)
+
+// FormatOptions contains the timestamp and caller options shared by Formatters
+type FormatOptions struct {
+	// TimeLayout is the layout used to render the record time, in the style of time.Time.Format. If empty,
+	// the time is omitted.
+	TimeLayout string
+
+	// UTC causes the record time to be rendered in UTC rather than the local time zone
+	UTC bool
+
+	// Caller selects how the caller's source location is rendered
+	Caller CallerStyle
+}
+
+// TextFormatOptionsFromLogFlags derives FormatOptions from log flags as used by log.Logger, such that
+// a TextFormatter produces the same timestamp and caller as a log.Logger with the same flags.
+func TextFormatOptionsFromLogFlags(flag int) FormatOptions {
+	opts := FormatOptions{}
+	layout := ""
+	if flag&log.Ldate != 0 {
+		layout = "2006/01/02"
+	}
+	if flag&(log.Ltime|log.Lmicroseconds) != 0 {
+		if layout != "" {
+			layout += " "
+		}
+		layout += "15:04:05"
+		if flag&log.Lmicroseconds != 0 {
+			layout += ".000000"
+		}
+	}
+	opts.TimeLayout = layout
+	opts.UTC = flag&log.LUTC != 0
+	opts.Caller = callerStyleFromLogFlags(flag)
+	return opts
+}
+
+// FormatOptionsFromLogFlags derives FormatOptions for structured formats such as JSON and logfmt from log
+// flags as used by log.Logger. The date and time are rendered in RFC 3339 format; log.Ldate and log.Ltime
+// each enable the corresponding part, and log.Lmicroseconds adds microseconds.
+func FormatOptionsFromLogFlags(flag int) FormatOptions {
+	opts := FormatOptions{}
+	layout := ""
+	if flag&log.Ldate != 0 {
+		layout = "2006-01-02"
+	}
+	if flag&(log.Ltime|log.Lmicroseconds) != 0 {
+		if layout != "" {
+			layout += "T"
+		}
+		layout += "15:04:05"
+		if flag&log.Lmicroseconds != 0 {
+			layout += ".000000"
+		}
+		if flag&log.Ldate != 0 {
+			layout += "Z07:00"
+		}
+	}
+	opts.TimeLayout = layout
+	opts.UTC = flag&log.LUTC != 0
+	opts.Caller = callerStyleFromLogFlags(flag)
+	return opts
+}
+
+// callerStyleFromLogFlags derives a CallerStyle from log flags. As with log.Logger, log.Lshortfile
+// overrides log.Llongfile.
+func callerStyleFromLogFlags(flag int) CallerStyle {
+	if flag&log.Lshortfile != 0 {
+		return CallerShort
+	}
+	if flag&log.Llongfile != 0 {
+		return CallerLong
+	}
+	return CallerNone
+}
+
+// appendTime appends the formatted record time to b. Nothing is appended if the time is omitted.
+func (o *FormatOptions) appendTime(b []byte, t time.Time) []byte {
+	if o.TimeLayout == "" {
+		return b
+	}
+	if o.UTC {
+		t = t.UTC()
+	}
+	return t.AppendFormat(b, o.TimeLayout)
+}
+
+// caller returns the formatted caller source location for a program counter, or an empty string if
+// the caller is omitted or unknown
+func (o *FormatOptions) caller(pc uintptr) string {
+	if o.Caller == CallerNone || pc == 0 {
+		return ""
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.File == "" {
+		return ""
+	}
+	file := frame.File
+	if o.Caller == CallerShort {
+		file = filepath.Base(file)
+	}
+	return file + ":" + strconv.Itoa(frame.Line)
+}
+
+// WriterHandler is a Handler that renders Records with a Formatter and writes them to an io.Writer.
+// Each record is written with a single call to Write, serialized by a mutex, so that records from
+// many goroutines are never interleaved.
+type WriterHandler struct {
+	mu        sync.Mutex
+	w         io.Writer
+	formatter Formatter
+}
+
+// NewWriterHandler creates a Handler that renders Records with a Formatter and writes them to an io.Writer
+func NewWriterHandler(w io.Writer, formatter Formatter) *WriterHandler {
+	h := &WriterHandler{
+		w:         w,
+		formatter: formatter,
+	}
+	return h
+}
+
+// Handle formats a Record and writes it to the io.Writer
+func (h *WriterHandler) Handle(r *Record) error {
+	b := h.formatter.Format(make([]byte, 0, 256), r)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(b)
+	return err
+}
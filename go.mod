@@ -1,8 +1,8 @@
 module github.com/sammck-go/logger
 
-go 1.16
+go 1.21
 
 require (
 	github.com/spf13/pflag v1.0.5
-	github.com/thediveo/enumflag v0.10.1
+	golang.org/x/sys v0.29.0
 )
@@ -0,0 +1,61 @@
+package logger
+
+// Handler is an interface for a component that receives structured log Records from a Logger
+// and delivers them to a destination. All output from a BasicLogger is passed through its
+// Handler. Level filtering is performed by the Logger before a Record is emitted, so a Handler
+// need not filter unless it wants a more restrictive level than the Logger.
+//
+// Handle may be called concurrently from multiple goroutines.
+type Handler interface {
+	// Handle processes a single log Record. An error is returned if the record could not be
+	// delivered.
+	Handle(r *Record) error
+}
+
+// HandlerFunc is an adapter that allows an ordinary function to be used as a Handler
+type HandlerFunc func(r *Record) error
+
+// Handle calls f(r)
+func (f HandlerFunc) Handle(r *Record) error {
+	return f(r)
+}
+
+// rawLoggerHandler is a Handler that renders Records as traditional text lines and writes them
+// to a RawLogger
+type rawLoggerHandler struct {
+	logger   RawLogger
+	levelTag LevelTag
+}
+
+// NewRawLoggerHandler creates a Handler that renders each Record as a single line of text (see
+// Record.Text) and passes it to a RawLogger, such as a standard golang *log.Logger. If logger
+// is already a Handler, it is returned unchanged.
+func NewRawLoggerHandler(logger RawLogger) Handler {
+	return newRawLoggerHandler(logger, LevelTag{})
+}
+
+// newRawLoggerHandler creates a Handler that renders each Record as a single line of text
+// with an optional level tag, and passes it to a RawLogger. If logger is already a Handler, it is
+// returned unchanged, and the level tag is not applied.
+func newRawLoggerHandler(logger RawLogger, levelTag LevelTag) Handler {
+	h, ok := logger.(Handler)
+	if ok {
+		return h
+	}
+	return &rawLoggerHandler{logger: logger, levelTag: levelTag}
+}
+
+// Handle renders a Record as text and passes it to the RawLogger
+func (h *rawLoggerHandler) Handle(r *Record) error {
+	return h.logger.Output(r.CallDepth+1, string(h.levelTag.appendText(nil, r)))
+}
+
+// GetLogLevel returns the log level of the wrapped RawLogger if it implements GetLogLeveler;
+// otherwise LogLevelTrace
+func (h *rawLoggerHandler) GetLogLevel() LogLevel {
+	gll, ok := h.logger.(GetLogLeveler)
+	if ok {
+		return gll.GetLogLevel()
+	}
+	return LogLevelTrace
+}
@@ -0,0 +1,211 @@
+package logger
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultJournalSocket is the path of the socket on which systemd-journald receives entries in its native protocol
+const DefaultJournalSocket = "/run/systemd/journal/socket"
+
+// JournalOptions configures a JournalSink
+type JournalOptions struct {
+	// Path is the path of the journald socket. If empty, DefaultJournalSocket is used.
+	Path string
+
+	// Identifier is the SYSLOG_IDENTIFIER of each entry. If empty, the base name of the program is used.
+	Identifier string
+}
+
+// JournalSink is a Handler that sends Records to systemd-journald using its native protocol, so that
+// they can be filtered with journalctl; e.g., "journalctl -p warning". Each entry has the fields:
+//
+//	MESSAGE            the record's message, without its prefix or fields
+//	PRIORITY           the syslog severity of the record's level, as by LevelTagSyslog
+//	SYSLOG_IDENTIFIER  the identifier given by JournalOptions
+//	LOGGER_PREFIX      the record's prefix chain, if any (see Record.Prefix)
+//	CODE_FILE          the source file of the logging call site, if known
+//	CODE_LINE          the line number of the logging call site, if known
+//	CODE_FUNC          the function of the logging call site, if known
+//
+// followed by the record's fields, with keys converted to journal field names (see JournalFieldName). A field
+// whose name collides with one of these is renamed with a "FIELDS_" prefix, so that journald's own fields,
+// such as MESSAGE and PRIORITY, are not duplicated. On Linux, entries too large to be sent as a single
+// datagram are passed to journald in a sealed memfd.
+//
+// If sending an entry fails, JournalSink reconnects and tries again once, so that logging continues
+// after journald is restarted.
+type JournalSink struct {
+	path       string
+	identifier string
+
+	mu     sync.Mutex
+	conn   *net.UnixConn
+	closed bool
+}
+
+// NewJournalSink creates a JournalSink and connects it to the journald socket
+func NewJournalSink(opts JournalOptions) (*JournalSink, error) {
+	s := &JournalSink{
+		path:       opts.Path,
+		identifier: opts.Identifier,
+	}
+	if s.path == "" {
+		s.path = DefaultJournalSocket
+	}
+	if s.identifier == "" {
+		s.identifier = filepath.Base(os.Args[0])
+	}
+
+	err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewJournalLogger creates a new Logger that sends its output to systemd-journald, with an optional prefix
+// and a loglevel. See NewJournalSink.
+func NewJournalLogger(opts JournalOptions, prefix string, logLevel LogLevel) (Logger, error) {
+	s, err := NewJournalSink(opts)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithHandler(s, prefix, logLevel), nil
+}
+
+// Handle formats a Record as a journal entry and sends it to journald, reconnecting if necessary
+func (s *JournalSink) Handle(r *Record) error {
+	entry := s.appendEntry(make([]byte, 0, 512), r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return os.ErrClosed
+	}
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			err = s.connect()
+			if err != nil {
+				return err
+			}
+		}
+		err = s.send(entry)
+		if err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+// Close closes the connection to journald. Subsequent records fail with os.ErrClosed.
+func (s *JournalSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// connect connects to the journald socket. s.mu must be held, or s must not yet be shared.
+func (s *JournalSink) connect() error {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: s.path, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+// appendEntry appends the native protocol serialization of the journal entry for a Record to b
+func (s *JournalSink) appendEntry(b []byte, r *Record) []byte {
+	b = appendJournalField(b, "MESSAGE", r.Message)
+	b = appendJournalField(b, "PRIORITY", strconv.Itoa(syslogSeverity(r.Level)))
+	b = appendJournalField(b, "SYSLOG_IDENTIFIER", s.identifier)
+	if len(r.PrefixPath) > 0 {
+		b = appendJournalField(b, "LOGGER_PREFIX", r.Prefix())
+	}
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		if frame.File != "" {
+			b = appendJournalField(b, "CODE_FILE", frame.File)
+			b = appendJournalField(b, "CODE_LINE", strconv.Itoa(frame.Line))
+		}
+		if frame.Function != "" {
+			b = appendJournalField(b, "CODE_FUNC", frame.Function)
+		}
+	}
+	for _, f := range r.Fields {
+		b = appendJournalField(b, JournalFieldName(f.Key), fieldValueString(f.Value))
+	}
+	return b
+}
+
+// journalReservedNames are the journal field names used by JournalSink for record attributes
+var journalReservedNames = map[string]bool{
+	"MESSAGE":           true,
+	"PRIORITY":          true,
+	"SYSLOG_IDENTIFIER": true,
+	"LOGGER_PREFIX":     true,
+	"CODE_FILE":         true,
+	"CODE_LINE":         true,
+	"CODE_FUNC":         true,
+}
+
+// appendJournalField appends a field in the journald native protocol to b. Values that contain a newline
+// are serialized as the name, a newline, the value's length as a little-endian 64-bit integer, and the value.
+func appendJournalField(b []byte, name string, value string) []byte {
+	b = append(b, name...)
+	if strings.IndexByte(value, '\n') < 0 {
+		b = append(b, '=')
+		b = append(b, value...)
+		return append(b, '\n')
+	}
+	b = append(b, '\n')
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(value)))
+	b = append(b, value...)
+	return append(b, '\n')
+}
+
+// JournalFieldName converts a field key to a valid journal field name, by converting letters to uppercase
+// and replacing other characters that are not digits with '_'. Since journal field names may not begin with
+// '_' or a digit, and are limited to 64 characters, such names are prefixed with "F" and long names are
+// truncated. Names used by JournalSink for record attributes, such as MESSAGE, are prefixed with "FIELDS_".
+func JournalFieldName(key string) string {
+	b := make([]byte, 0, len(key)+1)
+	if key == "" || !(key[0] >= 'a' && key[0] <= 'z' || key[0] >= 'A' && key[0] <= 'Z') {
+		b = append(b, 'F')
+	}
+	for i := 0; i < len(key) && len(b) < 64; i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			c -= 'a' - 'A'
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		default:
+			c = '_'
+		}
+		b = append(b, c)
+	}
+	name := string(b)
+	if journalReservedNames[name] {
+		name = "FIELDS_" + name
+	}
+	return name
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// send sends a serialized entry to journald. An entry that is too large for a single datagram is written to a
+// sealed memfd, which is passed to journald as an SCM_RIGHTS control message. s.mu must be held.
+func (s *JournalSink) send(entry []byte) error {
+	_, err := s.conn.Write(entry)
+	if errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS) {
+		return s.sendMemfd(entry)
+	}
+	return err
+}
+
+// sendMemfd writes a serialized entry to a sealed memfd and passes it to journald. s.mu must be held.
+func (s *JournalSink) sendMemfd(entry []byte) error {
+	fd, err := unix.MemfdCreate("logger-journal", unix.MFD_CLOEXEC|unix.MFD_ALLOW_SEALING)
+	if err != nil {
+		return err
+	}
+	f := os.NewFile(uintptr(fd), "logger-journal")
+	defer f.Close()
+	_, err = f.Write(entry)
+	if err != nil {
+		return err
+	}
+	_, err = unix.FcntlInt(uintptr(fd), unix.F_ADD_SEALS,
+		unix.F_SEAL_SHRINK|unix.F_SEAL_GROW|unix.F_SEAL_WRITE|unix.F_SEAL_SEAL)
+	if err != nil {
+		return err
+	}
+	// WriteMsgUnix cannot be used with a connected datagram socket, so the message is sent directly
+	raw, err := s.conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var sendErr error
+	err = raw.Write(func(sfd uintptr) bool {
+		sendErr = unix.Sendmsg(int(sfd), nil, unix.UnixRights(fd), nil, 0)
+		return sendErr != unix.EAGAIN
+	})
+	if err != nil {
+		return err
+	}
+	return sendErr
+}
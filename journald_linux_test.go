@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// parseJournalEntry parses an entry in the journald native protocol
+func parseJournalEntry(t *testing.T, b []byte) map[string]string {
+	fields := map[string]string{}
+	for len(b) > 0 {
+		i := 0
+		for i < len(b) && b[i] != '=' && b[i] != '\n' {
+			i++
+		}
+		if i == len(b) {
+			t.Fatalf("Truncated journal entry: %q", b)
+		}
+		name := string(b[:i])
+		if b[i] == '=' {
+			j := i + 1
+			for j < len(b) && b[j] != '\n' {
+				j++
+			}
+			fields[name] = string(b[i+1 : j])
+			b = b[j+1:]
+			continue
+		}
+		n := int(binary.LittleEndian.Uint64(b[i+1:]))
+		fields[name] = string(b[i+9 : i+9+n])
+		b = b[i+10+n:]
+	}
+	return fields
+}
+
+// readJournalEntry reads a journal entry from a listener, either as a datagram or from a passed file descriptor
+func readJournalEntry(t *testing.T, ln *net.UnixConn) (map[string]string, bool) {
+	buf := make([]byte, 1<<16)
+	oob := make([]byte, 256)
+	ln.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, oobn, _, _, err := ln.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oobn == 0 {
+		return parseJournalEntry(t, buf[:n]), false
+	}
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("Expected one control message; got %d (%v)", len(msgs), err)
+	}
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("Expected one file descriptor; got %d (%v)", len(fds), err)
+	}
+	f := os.NewFile(uintptr(fds[0]), "memfd")
+	defer f.Close()
+	b, err := io.ReadAll(io.NewSectionReader(f, 0, 1<<30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parseJournalEntry(t, b), true
+}
+
+func TestJournalSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "socket")
+	ln, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	lg, err := NewJournalLogger(JournalOptions{Path: path, Identifier: "myapp"}, "TestJournalSink", LogLevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg = lg.ForkLogStr("conn 3").ForkWith("conn-id", 42, "2nd", "x")
+	_, file, line, _ := runtime.Caller(0)
+	lg.WLogf("line one\nline two")
+	lg.DLogf("not sent")
+
+	fields, passed := readJournalEntry(t, ln)
+	if passed {
+		t.Errorf("Expected a small entry to be sent as a datagram")
+	}
+	expected := map[string]string{
+		"MESSAGE":           "line one\nline two",
+		"PRIORITY":          "4",
+		"SYSLOG_IDENTIFIER": "myapp",
+		"LOGGER_PREFIX":     "TestJournalSink: conn 3",
+		"CODE_FILE":         file,
+		"CODE_LINE":         strconv.Itoa(line + 1),
+		"CODE_FUNC":         "github.com/sammck-go/logger.TestJournalSink",
+		"CONN_ID":           "42",
+		"F2ND":              "x",
+	}
+	for name, value := range expected {
+		if fields[name] != value {
+			t.Errorf("Expected %s=%q; got %q", name, value, fields[name])
+		}
+	}
+	if len(fields) != len(expected) {
+		t.Errorf("Expected %d fields; got %v", len(expected), fields)
+	}
+
+	// An entry too large for a datagram is passed in a memfd
+	large := strings.Repeat("0123456789", 1<<17)
+	lg.ELog(large)
+	fields, passed = readJournalEntry(t, ln)
+	if !passed {
+		t.Errorf("Expected a large entry to be passed in a memfd")
+	}
+	if fields["MESSAGE"] != large || fields["PRIORITY"] != "3" {
+		t.Errorf("Expected large entry at priority 3; got %d bytes at priority %s", len(fields["MESSAGE"]), fields["PRIORITY"])
+	}
+
+	// Fields that collide with the sink's own fields are renamed
+	lg.WLogw("reserved", "message", "m", "Priority", 1, "code-line", 7)
+	fields, _ = readJournalEntry(t, ln)
+	if fields["MESSAGE"] != "reserved" ||
+		fields["PRIORITY"] != "4" || fields["FIELDS_MESSAGE"] != "m" || fields["FIELDS_PRIORITY"] != "1" ||
+		fields["FIELDS_CODE_LINE"] != "7" || fields["CODE_LINE"] == "7" {
+		t.Errorf("Unexpected fields %v", fields)
+	}
+	if JournalFieldName("syslog_identifier") != "FIELDS_SYSLOG_IDENTIFIER" || JournalFieldName("message_id") != "MESSAGE_ID" {
+		t.Errorf("Unexpected names %s, %s", JournalFieldName("syslog_identifier"), JournalFieldName("message_id"))
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package logger
+
+// send sends a serialized entry to journald as a single datagram. s.mu must be held.
+func (s *JournalSink) send(entry []byte) error {
+	_, err := s.conn.Write(entry)
+	return err
+}
@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"encoding/json"
+	"strconv"
+	"unicode/utf8"
+)
+
+// JSONFormatter is a Formatter that renders each Record as a single-line JSON object followed by a
+// newline. The object contains "time" (if enabled), "level" (omitted for records without a level),
+// "prefix" (the joined prefix), "prefix_path" (an array of fork prefix segments), "msg", "caller" (if
+// enabled), and the record's fields. A field whose key collides with one of these is renamed with
+// a "fields." prefix.
+type JSONFormatter struct {
+	FormatOptions
+}
+
+// NewJSONFormatter creates a JSONFormatter with the given options
+func NewJSONFormatter(opts FormatOptions) *JSONFormatter {
+	return &JSONFormatter{FormatOptions: opts}
+}
+
+// jsonReservedKeys are the keys used by JSONFormatter for record attributes
+var jsonReservedKeys = map[string]bool{
+	"time":        true,
+	"level":       true,
+	"prefix":      true,
+	"prefix_path": true,
+	"msg":         true,
+	"caller":      true,
+}
+
+// Format appends the JSON rendering of a Record, including a trailing newline, to b
+func (f *JSONFormatter) Format(b []byte, r *Record) []byte {
+	b = append(b, '{')
+	if f.TimeLayout != "" {
+		b = append(b, `"time":"`...)
+		b = f.appendTime(b, r.Time)
+		b = append(b, `",`...)
+	}
+	if r.Level != LogLevelUnknown {
+		b = append(b, `"level":`...)
+		b = appendJSONString(b, r.Level.String())
+		b = append(b, ',')
+	}
+	b = append(b, `"prefix":`...)
+	b = appendJSONString(b, r.Prefix())
+	b = append(b, `,"prefix_path":[`...)
+	for i, segment := range r.PrefixPath {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendJSONString(b, segment)
+	}
+	b = append(b, `],"msg":`...)
+	b = appendJSONString(b, r.Message)
+	caller := f.caller(r.PC)
+	if caller != "" {
+		b = append(b, `,"caller":`...)
+		b = appendJSONString(b, caller)
+	}
+	for _, field := range r.Fields {
+		key := field.Key
+		if jsonReservedKeys[key] {
+			key = "fields." + key
+		}
+		b = append(b, ',')
+		b = appendJSONString(b, key)
+		b = append(b, ':')
+		b = appendJSONValue(b, field.Value)
+	}
+	return append(b, "}\n"...)
+}
+
+// appendJSONValue appends the JSON encoding of a field value to b. Errors are rendered as their
+// message string, or "<nil>" for a nil pointer; values that cannot be encoded as JSON are rendered as a string in the style of fmt.Sprint.
+func appendJSONValue(b []byte, v interface{}) []byte {
+	switch x := v.(type) {
+	case nil:
+		return append(b, "null"...)
+	case string:
+		return appendJSONString(b, x)
+	case bool:
+		return strconv.AppendBool(b, x)
+	case int:
+		return strconv.AppendInt(b, int64(x), 10)
+	case int64:
+		return strconv.AppendInt(b, x, 10)
+	case uint64:
+		return strconv.AppendUint(b, x, 10)
+	case error:
+		return appendJSONString(b, callStringMethod(v, "Error", x.Error))
+	}
+	enc, err := json.Marshal(v)
+	if err != nil {
+		return appendJSONString(b, fieldValueString(v))
+	}
+	return append(b, enc...)
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString appends s to b as a quoted JSON string
+func appendJSONString(b []byte, s string) []byte {
+	b = append(b, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			b = append(b, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hexDigits[r&0xf])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// LevelRule overrides the log level of loggers whose prefix matches a pattern
+type LevelRule struct {
+	// Pattern is matched against the full prefix of a logger (the fork prefix segments joined with ": ", as
+	// returned by Logger.Prefix). '*' matches any sequence of characters, including ": ", and '?' matches any
+	// single character. Other characters match themselves. For example, "db: *" matches all loggers
+	// forked from a logger with prefix "db", and "*: conn 17" matches any logger whose last prefix segment
+	// is "conn 17".
+	Pattern string
+
+	// Level is the log level for matching loggers
+	Level LogLevel
+}
+
+// LevelRules is a table of LevelRules, keyed by pattern, that is consulted by loggers to determine their log level.
+// The rules may be changed at any time, and changes take effect immediately on all loggers that use the
+// table, including loggers that already exist.
+//
+// A logger whose level has been explicitly set (with SetLogLevel or at construction) always uses that
+// level. Otherwise, if one or more rules match the logger's prefix, the level of the last matching rule in
+// the table is used. Otherwise, the level is inherited from the logger it was forked from, which may
+// itself be determined by a rule.
+type LevelRules struct {
+	mu    sync.RWMutex
+	rules []LevelRule
+	// generation is incremented whenever the rules change, invalidating cached matches
+	generation uint32
+}
+
+// NewLevelRules creates a new LevelRules table with initial rules. If several rules have the same pattern,
+// the last one is used.
+func NewLevelRules(rules ...LevelRule) *LevelRules {
+	lr := &LevelRules{}
+	for _, rule := range rules {
+		lr.setLocked(rule.Pattern, rule.Level)
+	}
+	return lr
+}
+
+// Set adds a rule to the end of the table, or replaces the level of an existing rule with the same pattern
+func (lr *LevelRules) Set(pattern string, logLevel LogLevel) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	lr.setLocked(pattern, logLevel)
+	atomic.AddUint32(&lr.generation, 1)
+}
+
+// setLocked adds or replaces a rule. The caller must hold the write lock.
+func (lr *LevelRules) setLocked(pattern string, logLevel LogLevel) {
+	for i := range lr.rules {
+		if lr.rules[i].Pattern == pattern {
+			lr.rules[i].Level = logLevel
+			return
+		}
+	}
+	lr.rules = append(lr.rules, LevelRule{Pattern: pattern, Level: logLevel})
+}
+
+// Remove deletes the rule with the given pattern, if any
+func (lr *LevelRules) Remove(pattern string) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	for i := range lr.rules {
+		if lr.rules[i].Pattern == pattern {
+			lr.rules = append(lr.rules[:i:i], lr.rules[i+1:]...)
+			break
+		}
+	}
+	atomic.AddUint32(&lr.generation, 1)
+}
+
+// Replace replaces all of the rules in the table
+func (lr *LevelRules) Replace(rules ...LevelRule) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	lr.rules = nil
+	for _, rule := range rules {
+		lr.setLocked(rule.Pattern, rule.Level)
+	}
+	atomic.AddUint32(&lr.generation, 1)
+}
+
+// Rules returns a copy of the rules in the table, in order
+func (lr *LevelRules) Rules() []LevelRule {
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+	return append([]LevelRule(nil), lr.rules...)
+}
+
+// Match returns the level of the last rule whose pattern matches a prefix. false is returned if no rule matches.
+func (lr *LevelRules) Match(prefix string) (LogLevel, bool) {
+	logLevel, _ := lr.match(prefix)
+	return logLevel, logLevel != LogLevelUnknown
+}
+
+// match returns the level of the last rule whose pattern matches a prefix, or LogLevelUnknown if no rule matches,
+// along with the generation of the rules that were consulted
+func (lr *LevelRules) match(prefix string) (LogLevel, uint32) {
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+	generation := atomic.LoadUint32(&lr.generation)
+	for i := len(lr.rules) - 1; i >= 0; i-- {
+		if globMatch(lr.rules[i].Pattern, prefix) {
+			return lr.rules[i].Level, generation
+		}
+	}
+	return LogLevelUnknown, generation
+}
+
+// cachedMatch returns the level of the last rule whose pattern matches a prefix, or LogLevelUnknown if no rule
+// matches, using and updating a cache word that is valid as long as the rules are unchanged. The cache word
+// holds the generation plus one in its upper 32 bits, and the level in its lower 32 bits.
+func (lr *LevelRules) cachedMatch(prefix string, cache *uint64) LogLevel {
+	cached := atomic.LoadUint64(cache)
+	if uint32(cached>>32) == atomic.LoadUint32(&lr.generation)+1 {
+		return LogLevel(int32(uint32(cached)))
+	}
+	logLevel, generation := lr.match(prefix)
+	atomic.StoreUint64(cache, uint64(generation+1)<<32|uint64(uint32(int32(logLevel))))
+	return logLevel
+}
+
+// globMatch returns true if s matches a pattern in which '*' matches any sequence of characters and '?' matches
+// any single character
+func globMatch(pattern string, s string) bool {
+	p := []rune(pattern)
+	r := []rune(s)
+	pi, ri := 0, 0
+	starP, starR := -1, 0
+	for ri < len(r) {
+		if pi < len(p) && (p[pi] == '?' || (p[pi] != '*' && p[pi] == r[ri])) {
+			pi++
+			ri++
+		} else if pi < len(p) && p[pi] == '*' {
+			starP = pi
+			starR = ri
+			pi++
+		} else if starP >= 0 {
+			pi = starP + 1
+			starR++
+			ri = starR
+		} else {
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+)
+
+// LevelTagStyle selects how the log level of each record is rendered in text output
+type LevelTagStyle int
+
+const (
+	// LevelTagNone omits the log level from text output. This is the default.
+	LevelTagNone LevelTagStyle = iota
+
+	// LevelTagBracketed renders the log level in square brackets, e.g., "[WARNING]"
+	LevelTagBracketed LevelTagStyle = iota
+
+	// LevelTagPlain renders the log level as a bare word, e.g., "WARNING"
+	LevelTagPlain LevelTagStyle = iota
+
+	// LevelTagSyslog renders the syslog severity of the log level in angle brackets, e.g., "<4>".
+	// Short and Lowercase have no effect on this style.
+	LevelTagSyslog LevelTagStyle = iota
+)
+
+// LevelTagPosition selects where the level tag is placed in a text output line
+type LevelTagPosition int
+
+const (
+	// LevelTagBeforePrefix places the level tag before the logger's prefix (after any timestamp
+	// added by the raw logger). This is the default.
+	LevelTagBeforePrefix LevelTagPosition = iota
+
+	// LevelTagAfterPrefix places the level tag between the logger's prefix and the message
+	LevelTagAfterPrefix LevelTagPosition = iota
+
+	// LevelTagEnd places the level tag at the end of the line, after any fields
+	LevelTagEnd LevelTagPosition = iota
+)
+
+// LevelTag describes how the log level of each record is rendered in text output. The zero value
+// omits the level. Records emitted without a level (e.g., by Print) are never tagged.
+type LevelTag struct {
+	// Style selects the form of the tag
+	Style LevelTagStyle
+
+	// Position selects where the tag is placed in the line
+	Position LevelTagPosition
+
+	// Short selects single-letter level names, e.g., "W" instead of "WARNING"
+	Short bool
+
+	// Lowercase selects lowercase level names, e.g., "warning" instead of "WARNING"
+	Lowercase bool
+}
+
+// syslogSeverities maps LogLevel values to syslog severities (RFC 5424 section 6.2.1)
+var syslogSeverities = [...]int{
+	LogLevelUnknown: 6,
+	LogLevelPanic:   0,
+	LogLevelFatal:   2,
+	LogLevelError:   3,
+	LogLevelWarning: 4,
+	LogLevelInfo:    6,
+	LogLevelDebug:   7,
+	LogLevelTrace:   7,
+}
+
+// syslogSeverity returns the syslog severity corresponding to a LogLevel
+func syslogSeverity(logLevel LogLevel) int {
+	if logLevel < LogLevelUnknown || logLevel > LogLevelTrace {
+		logLevel = LogLevelUnknown
+	}
+	return syslogSeverities[logLevel]
+}
+
+// levelName returns the name of a level as it appears in a tag
+func (t LevelTag) levelName(logLevel LogLevel) string {
+	name := logLevel.String()
+	if t.Short {
+		name = name[:1]
+	}
+	if !t.Lowercase {
+		name = strings.ToUpper(name)
+	}
+	return name
+}
+
+// appendTag appends the rendered tag for logLevel to b, without any separating space
+func (t LevelTag) appendTag(b []byte, logLevel LogLevel) []byte {
+	switch t.Style {
+	case LevelTagBracketed:
+		b = append(b, '[')
+		b = append(b, t.levelName(logLevel)...)
+		b = append(b, ']')
+	case LevelTagPlain:
+		b = append(b, t.levelName(logLevel)...)
+	case LevelTagSyslog:
+		b = append(b, '<')
+		b = strconv.AppendInt(b, int64(syslogSeverity(logLevel)), 10)
+		b = append(b, '>')
+	}
+	return b
+}
+
+// appendText appends the text rendering of a record (see Record.AppendText) to b, with the
+// level tag inserted in the configured position
+func (t LevelTag) appendText(b []byte, r *Record) []byte {
+	if t.Style == LevelTagNone || r.Level == LogLevelUnknown {
+		return r.AppendText(b)
+	}
+	switch t.Position {
+	case LevelTagAfterPrefix:
+		for _, segment := range r.PrefixPath {
+			b = append(b, segment...)
+			b = append(b, ": "...)
+		}
+		b = t.appendTag(b, r.Level)
+		b = append(b, ' ')
+		b = append(b, r.Message...)
+		b = appendFields(b, r.Fields)
+	case LevelTagEnd:
+		b = r.AppendText(b)
+		b = append(b, ' ')
+		b = t.appendTag(b, r.Level)
+	default:
+		b = t.appendTag(b, r.Level)
+		b = append(b, ' ')
+		b = r.AppendText(b)
+	}
+	return b
+}
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"sync/atomic"
+)
+
+// LevelVar is a LogLevel variable that can be safely read and changed from multiple goroutines.
+// A LevelVar may inherit from a parent LevelVar; until its own level is set, it reports the
+// level of its parent. Loggers forked with ForkLogStr, ForkWith, etc. inherit from the LevelVar of
+// the logger they were forked from, so a single SetLogLevel call changes the verbosity of an entire
+// subtree of loggers at runtime, except for descendants whose levels have been explicitly set.
+//
+// A LevelVar may also consult a LevelRules table: if its own level has not been set, and a rule
+// matches the prefix of the logger that owns it, the rule's level is used instead of the parent's.
+// Rules from a level specification (see WithLevelSpec) are kept in a separate table that is
+// consulted before it.
+type LevelVar struct {
+	// ruleCache caches the result of matching prefix against rules. It is accessed atomically and
+	// must be the first field for 64-bit alignment.
+	ruleCache uint64
+	// specRuleCache caches the result of matching prefix against specRules. It is accessed atomically.
+	specRuleCache uint64
+	// level is the explicitly set LogLevel, or LogLevelUnknown if the level is inherited
+	level int32
+	// parent is the LevelVar from which the level is inherited, or nil
+	parent *LevelVar
+	// rules is the LevelRules table consulted before the parent, or nil
+	rules *LevelRules
+	// specRules is the table of level specification rules consulted before rules, or nil
+	specRules *LevelRules
+	// prefix is the logger prefix that is matched against rules
+	prefix string
+}
+
+// NewLevelVar creates a new LevelVar with no parent, initialized to a given level
+func NewLevelVar(logLevel LogLevel) *LevelVar {
+	v := &LevelVar{
+		level: int32(logLevel),
+	}
+	return v
+}
+
+// NewRuleLevelVar creates a new LevelVar with no parent, initialized to a given level, that consults
+// a LevelRules table with a given logger prefix when its level is not set
+func NewRuleLevelVar(logLevel LogLevel, rules *LevelRules, prefix string) *LevelVar {
+	v := &LevelVar{
+		level:  int32(logLevel),
+		rules:  rules,
+		prefix: prefix,
+	}
+	return v
+}
+
+// NewChild creates a new LevelVar that inherits its level from v until its own level is set. The
+// child consults the same LevelRules table as v, if any, with the same prefix.
+func (v *LevelVar) NewChild() *LevelVar {
+	return v.newForkChild(v.prefix)
+}
+
+// newForkChild creates a new LevelVar that inherits its level from v until its own level is set, and
+// consults the same LevelRules tables as v, if any, with a new logger prefix
+func (v *LevelVar) newForkChild(prefix string) *LevelVar {
+	child := &LevelVar{
+		level:     int32(LogLevelUnknown),
+		parent:    v,
+		rules:     v.rules,
+		specRules: v.specRules,
+		prefix:    prefix,
+	}
+	return child
+}
+
+// newRuleChild creates a new LevelVar that inherits its level from v until its own level is set, and consults
+// a LevelRules table with a logger prefix first
+func (v *LevelVar) newRuleChild(rules *LevelRules, prefix string) *LevelVar {
+	child := v.newForkChild(prefix)
+	child.rules = rules
+	return child
+}
+
+// Level returns the current level. If the level has not been set, the level of the last LevelRule matching
+// the logger prefix is returned, with level specification rules taking precedence; if no rule matches, the
+// parent's level is returned.
+// LogLevelUnknown is returned if neither this LevelVar nor any of its ancestors has a level.
+func (v *LevelVar) Level() LogLevel {
+	for ; v != nil; v = v.parent {
+		logLevel := LogLevel(atomic.LoadInt32(&v.level))
+		if logLevel != LogLevelUnknown {
+			return logLevel
+		}
+		if v.specRules != nil {
+			logLevel = v.specRules.cachedMatch(v.prefix, &v.specRuleCache)
+			if logLevel != LogLevelUnknown {
+				return logLevel
+			}
+		}
+		if v.rules != nil {
+			logLevel = v.rules.cachedMatch(v.prefix, &v.ruleCache)
+			if logLevel != LogLevelUnknown {
+				return logLevel
+			}
+		}
+	}
+	return LogLevelUnknown
+}
+
+// Rules returns the LevelRules table consulted by the LevelVar, or nil
+func (v *LevelVar) Rules() *LevelRules {
+	return v.rules
+}
+
+// GetLogLevel returns the current level. It is the same as Level, and allows a LevelVar to be used
+// as a GetLogLeveler.
+func (v *LevelVar) GetLogLevel() LogLevel {
+	return v.Level()
+}
+
+// Set sets the level. Setting LogLevelUnknown causes the level to be inherited from the parent again.
+func (v *LevelVar) Set(logLevel LogLevel) {
+	atomic.StoreInt32(&v.level, int32(logLevel))
+}
+
+// IsSet returns true if the level has been explicitly set rather than inherited from the parent
+func (v *LevelVar) IsSet() bool {
+	return LogLevel(atomic.LoadInt32(&v.level)) != LogLevelUnknown
+}
+
+// Parent returns the LevelVar from which the level is inherited, or nil
+func (v *LevelVar) Parent() *LevelVar {
+	return v.parent
+}
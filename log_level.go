@@ -87,3 +87,14 @@ func (x *LogLevel) FromString(s string) error {
 	}
 	return err
 }
+
+// Set initializes a LogLevel from a string. Upper/lowercase are accepted. Together with
+// String and Type, this allows a LogLevel to be used as a flag.Value or pflag.Value.
+func (x *LogLevel) Set(s string) error {
+	return x.FromString(s)
+}
+
+// Type returns the name of the LogLevel type, for use in flag usage messages
+func (x *LogLevel) Type() string {
+	return "level"
+}
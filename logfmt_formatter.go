@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"strconv"
+	"unicode/utf8"
+)
+
+// LogfmtFormatter is a Formatter that renders each Record as a single line of logfmt key=value pairs,
+// e.g.:
+//
+//	ts=2021-06-01T12:00:00Z level=info prefix="TestObj 3" msg="hello world" caller=main.go:12 conn_id=42
+//
+// The "ts" (if enabled), "level" (omitted for records without a level), "prefix" (omitted if empty),
+// "msg" and "caller" (if enabled) keys are followed by the record's fields. Values that are empty
+// or contain spaces, quotes, '=' or non-printable characters are quoted and escaped; characters that
+// are not valid in a key are replaced with '_'.
+type LogfmtFormatter struct {
+	FormatOptions
+}
+
+// NewLogfmtFormatter creates a LogfmtFormatter with the given options
+func NewLogfmtFormatter(opts FormatOptions) *LogfmtFormatter {
+	return &LogfmtFormatter{FormatOptions: opts}
+}
+
+// Format appends the logfmt rendering of a Record, including a trailing newline, to b
+func (f *LogfmtFormatter) Format(b []byte, r *Record) []byte {
+	start := len(b)
+	if f.TimeLayout != "" {
+		b = append(b, "ts="...)
+		b = appendMaybeQuoted(b, string(f.appendTime(nil, r.Time)))
+	}
+	if r.Level != LogLevelUnknown {
+		b = appendLogfmtKey(b, start, "level")
+		b = append(b, r.Level.String()...)
+	}
+	if len(r.PrefixPath) > 0 {
+		b = appendLogfmtKey(b, start, "prefix")
+		b = appendMaybeQuoted(b, r.Prefix())
+	}
+	b = appendLogfmtKey(b, start, "msg")
+	b = appendMaybeQuoted(b, r.Message)
+	caller := f.caller(r.PC)
+	if caller != "" {
+		b = appendLogfmtKey(b, start, "caller")
+		b = appendMaybeQuoted(b, caller)
+	}
+	for _, field := range r.Fields {
+		b = appendLogfmtKey(b, start, field.Key)
+		b = appendMaybeQuoted(b, fieldValueString(field.Value))
+	}
+	return append(b, '\n')
+}
+
+// appendLogfmtKey appends a separating space (unless the line beginning at start is empty), a key
+// with invalid characters replaced with '_', and '=' to b
+func appendLogfmtKey(b []byte, start int, key string) []byte {
+	if len(b) > start {
+		b = append(b, ' ')
+	}
+	if key == "" {
+		b = append(b, '_')
+	}
+	for _, c := range key {
+		if c <= ' ' || c == '=' || c == '"' || c == '\\' || c == utf8.RuneError || !strconv.IsPrint(c) {
+			c = '_'
+		}
+		b = append(b, string(c)...)
+	}
+	return append(b, '=')
+}
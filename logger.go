@@ -5,8 +5,9 @@ annotations.
 package logger
 
 import (
-	"log"
+	"io"
 	"os"
+	"strings"
 )
 
 // RawLogger is a minimal logging interface for an underlying logging component. A full-featured Logger implementation
@@ -103,6 +104,16 @@ type Logger interface {
 	// Arguments are formatted in the style of fmt.Sprintf
 	Logf(logLevel LogLevel, f string, args ...interface{})
 
+	// CdLogw outputs a message followed by structured key/value fields to a Logger with a given calldepth
+	// if the given logLevel is enabled. Then, if the given logLevel is LogLevelPanic or LogLevelFatal,
+	// exits appropriately. keysAndValues alternate between string keys and arbitrary values.
+	CdLogw(calldepth int, logLevel LogLevel, msg string, keysAndValues ...interface{})
+
+	// Logw outputs a message followed by structured key/value fields to a Logger if the given logLevel
+	// is enabled. Then, if the given logLevel is LogLevelPanic or LogLevelFatal, exits appropriately.
+	// keysAndValues alternate between string keys and arbitrary values.
+	Logw(logLevel LogLevel, msg string, keysAndValues ...interface{})
+
 	// CdLogErrorf outputs an error message with a given calldepth to a Logger iff logLevel is enabled,
 	// then returns an error object with a description string that has the
 	// logger's prefix. If the given logLevel is LogLevelPanic or LogLevelFatal, does not return.
@@ -163,6 +174,10 @@ type Logger interface {
 	// Arguments are formatted in the style of fmt.Sprintf.
 	ELogf(f string, args ...interface{})
 
+	// ELogw outputs a message followed by structured key/value fields if LogLevelError is enabled.
+	// keysAndValues alternate between string keys and arbitrary values.
+	ELogw(msg string, keysAndValues ...interface{})
+
 	// WLog outputs a formatted log message if LogLevelWarning is enabled.
 	// Arguments are formatted in the style of fmt.Sprint.
 	WLog(args ...interface{})
@@ -171,6 +186,10 @@ type Logger interface {
 	// Arguments are formatted in the style of fmt.Sprintf.
 	WLogf(f string, args ...interface{})
 
+	// WLogw outputs a message followed by structured key/value fields if LogLevelWarning is enabled.
+	// keysAndValues alternate between string keys and arbitrary values.
+	WLogw(msg string, keysAndValues ...interface{})
+
 	// ILog outputs a formatted log message if LogLevelInfo is enabled.
 	// Arguments are formatted in the style of fmt.Sprint.
 	ILog(args ...interface{})
@@ -179,6 +198,10 @@ type Logger interface {
 	// Arguments are formatted in the style of fmt.Sprintf.
 	ILogf(f string, args ...interface{})
 
+	// ILogw outputs a message followed by structured key/value fields if LogLevelInfo is enabled.
+	// keysAndValues alternate between string keys and arbitrary values.
+	ILogw(msg string, keysAndValues ...interface{})
+
 	// DLog outputs a formatted log message if LogLevelDebug is enabled.
 	// Arguments are formatted in the style of fmt.Sprint.
 	DLog(args ...interface{})
@@ -187,6 +210,10 @@ type Logger interface {
 	// Arguments are formatted in the style of fmt.Sprintf.
 	DLogf(f string, args ...interface{})
 
+	// DLogw outputs a message followed by structured key/value fields if LogLevelDebug is enabled.
+	// keysAndValues alternate between string keys and arbitrary values.
+	DLogw(msg string, keysAndValues ...interface{})
+
 	// TLog outputs a formatted log message if LogLevelTrace is enabled.
 	// Arguments are formatted in the style of fmt.Sprint.
 	TLog(args ...interface{})
@@ -195,6 +222,10 @@ type Logger interface {
 	// Arguments are formatted in the style of fmt.Sprintf.
 	TLogf(f string, args ...interface{})
 
+	// TLogw outputs a message followed by structured key/value fields if LogLevelTrace is enabled.
+	// keysAndValues alternate between string keys and arbitrary values.
+	TLogw(msg string, keysAndValues ...interface{})
+
 	// CdError generates an error object with a given calldepth and this logger's prefix.
 	// Arguments are formatted in the style of fmt.Sprint.
 	// Note: The raw logger's prefix, if any, is not included.
@@ -317,26 +348,122 @@ type Logger interface {
 	// Arguments are formatted in the style of fmt.Sprint
 	ForkLog(args ...interface{}) Logger
 
+	// ForkWith creates a new Logger that has the same prefix as an existing logger, and additional
+	// structured fields appended onto the existing logger's fields.
+	// keysAndValues alternate between string keys and arbitrary values.
+	ForkWith(keysAndValues ...interface{}) Logger
+
+	// Writer returns an io.WriteCloser that splits the bytes written to it into lines, and logs each line
+	// at the given level. Close logs any final partial line.
+	Writer(logLevel LogLevel) io.WriteCloser
+
 	// Prefix returns the Logger's prefix string (does not include ": " trailer).
 	// Does not include the raw logger's prefix, if any.
 	Prefix() string
 
-	// SetLogLevel sets the log level
+	// PrefixPath returns the individual prefix segments added by each fork of the Logger, outermost
+	// first. Does not include the raw logger's prefix, if any. The returned slice must not be modified.
+	PrefixPath() []string
+
+	// Fields returns the Logger's structured fields, including inherited fields. The returned
+	// slice must not be modified.
+	Fields() []Field
+
+	// SetLogLevel sets the log level. The new level also applies to all loggers forked from this
+	// logger that have not had their own levels set. Setting LogLevelUnknown causes the level to be
+	// inherited again from the logger this logger was forked from.
 	SetLogLevel(logLevel LogLevel)
 }
 
 // NewWithConfig creates a new Logger object from a configuration
 func NewWithConfig(cfg *Config) (Logger, error) {
-	parentLogger := cfg.parentLogger
-	if parentLogger == nil {
-		lw := cfg.logWriter
-		if lw == nil {
-			lw = os.Stderr
+	if cfg.err != nil {
+		return nil, cfg.err
+	}
+
+	sink := newSinkState(cfg.errorHandler)
+	var reopenable *ReopenableFile
+
+	handler := cfg.handler
+	if th, ok := handler.(*TeeHandler); ok {
+		// Destinations with a Writer use the configured format options
+		var err error
+		handler, err = th.withConfig(cfg)
+		if err != nil {
+			return nil, err
 		}
-		parentLogger = log.New(lw, "", cfg.flag)
+	}
+	if handler == nil {
+		parentLogger := cfg.parentLogger
+		if parentLogger == nil {
+			lw := cfg.logWriter
+			if cfg.logFile != "" && cfg.reopenable {
+				f, err := OpenReopenableFile(cfg.logFile)
+				if err != nil {
+					return nil, err
+				}
+				f.onError = sink.backgroundError
+				reopenable = f
+				lw = f
+			} else if cfg.logFile != "" && cfg.rotateOpts != nil {
+				f, err := NewRotatingFile(cfg.logFile, *cfg.rotateOpts)
+				if err != nil {
+					return nil, err
+				}
+				f.onError = sink.backgroundError
+				lw = f
+			} else if cfg.logFile != "" {
+				f, err := os.OpenFile(cfg.logFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+				if err != nil {
+					return nil, err
+				}
+				lw = f
+			}
+			if lw == nil {
+				lw = os.Stderr
+			}
+			formatter, err := cfg.formatter(lw)
+			if err != nil {
+				// Don't leak the log file opened above
+				if c, ok := lw.(io.Closer); ok && cfg.logFile != "" {
+					c.Close()
+				}
+				return nil, err
+			}
+			handler = NewWriterHandler(lw, formatter)
+		} else {
+			handler = newRawLoggerHandler(parentLogger, cfg.levelTag)
+		}
+	}
+	if cfg.asyncOpts != nil {
+		asyncOpts := *cfg.asyncOpts
+		if asyncOpts.ErrorHandler == nil {
+			asyncOpts.ErrorHandler = sink.asyncFailed
+		}
+		handler = NewAsyncHandler(handler, asyncOpts)
+	}
+	sink.handler = handler
+	if reopenable != nil && len(cfg.reopenSignals) > 0 {
+		reopenable.ReopenOnSignal(cfg.reopenSignals...)
 	}
 
-	lg := NewLogWrapper(parentLogger, cfg.prefix, cfg.logLevel)
+	var level *LevelVar
+	if cfg.levelVar != nil {
+		level = cfg.levelVar
+	} else if cfg.levelRules != nil || len(cfg.specRules) > 0 {
+		if cfg.logLevelSet {
+			level = NewRuleLevelVar(cfg.logLevel, cfg.levelRules, cfg.prefix)
+		} else {
+			// Rules matching the root prefix take precedence over the default level
+			level = NewLevelVar(cfg.logLevel).newRuleChild(cfg.levelRules, cfg.prefix)
+		}
+		if len(cfg.specRules) > 0 {
+			level.specRules = NewLevelRules(cfg.specRules...)
+		}
+	} else {
+		level = NewLevelVar(limitLogLevel(handler, cfg.logLevel))
+	}
+	lg := newBasicLogger(handler, prefixToPath(cfg.prefix), level, nil, sink)
 
 	return lg, nil
 }
@@ -353,10 +480,23 @@ func New(opts ...ConfigOption) (Logger, error) {
 // logging level of the base logger, since the base logger since the base logger will filter items passed
 // to it. As an optimization, the wrapper will limit the loglevel to the base logger's level if
 // it implements GetLogLevel(). If the base logger's loglevel subsequently changes, it is the caller's
-// responsibility to adjust the new wrapper's loglevel if desired.
+// responsibility to adjust the new wrapper's loglevel if desired. If the base logger is also a
+// Handler (such as another BasicLogger), records are passed to it without being flattened to text.
 func NewLogWrapper(logger RawLogger, prefix string, logLevel LogLevel) Logger {
+	return NewWithHandler(NewRawLoggerHandler(logger), prefix, logLevel)
+}
+
+// NewWithHandler creates a new Logger that emits structured Records to a Handler, with an optional
+// prefix and a loglevel. As an optimization, the loglevel is limited to the handler's level if it
+// implements GetLogLevel().
+func NewWithHandler(handler Handler, prefix string, logLevel LogLevel) Logger {
+	return newBasicLogger(handler, prefixToPath(prefix), NewLevelVar(limitLogLevel(handler, logLevel)), nil, newSinkState(nil))
+}
+
+// limitLogLevel returns a loglevel limited to a handler's level if it implements GetLogLevel()
+func limitLogLevel(handler Handler, logLevel LogLevel) LogLevel {
 	if logLevel > LogLevelFatal {
-		gll, ok := logger.(GetLogLeveler)
+		gll, ok := handler.(GetLogLeveler)
 		if ok {
 			gllLevel := gll.GetLogLevel()
 			if gllLevel < logLevel {
@@ -364,17 +504,35 @@ func NewLogWrapper(logger RawLogger, prefix string, logLevel LogLevel) Logger {
 			}
 		}
 	}
+	return logLevel
+}
+
+// prefixToPath converts a prefix string into a prefix path with a single segment, or an empty path
+// if the prefix is empty
+func prefixToPath(prefix string) []string {
+	if prefix == "" {
+		return nil
+	}
+	return []string{prefix}
+}
 
+// newBasicLogger creates a new BasicLogger that emits to a Handler with a prefix path, a LevelVar,
+// a set of structured fields and a sinkState. prefixPath and fields are retained and must not be modified.
+func newBasicLogger(handler Handler, prefixPath []string, level *LevelVar, fields []Field, sink *sinkState) *BasicLogger {
+	prefix := strings.Join(prefixPath, ": ")
 	prefixC := prefix
 	if prefixC != "" {
 		prefixC += ": "
 	}
 
 	l := &BasicLogger{
-		prefix:   prefix,
-		prefixC:  prefixC,
-		logger:   logger,
-		logLevel: logLevel,
+		prefix:     prefix,
+		prefixC:    prefixC,
+		prefixPath: prefixPath,
+		handler:    handler,
+		level:      level,
+		fields:     fields,
+		sink:       sink,
 	}
 	return l
 }
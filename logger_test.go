@@ -2,10 +2,31 @@ package logger
 
 import (
 	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"math/big"
+	"net"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
 )
 
 type TestObj struct {
@@ -123,3 +144,1693 @@ func TestLogging(t *testing.T) {
 	}
 	lf = nil
 }
+
+type captureRawLogger struct {
+	lines []string
+}
+
+func (c *captureRawLogger) Output(calldepth int, s string) error {
+	c.lines = append(c.lines, s)
+	return nil
+}
+
+func TestForkWith(t *testing.T) {
+	raw := &captureRawLogger{}
+	lg := NewLogWrapper(raw, "TestForkWith", LogLevelDebug)
+
+	conn := lg.ForkWith("conn_id", 42, "peer", "10.0.0.1:80")
+	conn.ILogw("accepted", "bytes", 17)
+	conn.ForkLogStr("TestObj 1").DLogf("hello %s", "world")
+	conn.ForkWith(F("note", "two words")).WLog("quoted")
+	lg.ILogf("no fields")
+
+	expectedLines := []string{
+		"TestForkWith: accepted conn_id=42 peer=10.0.0.1:80 bytes=17",
+		"TestForkWith: TestObj 1: hello world conn_id=42 peer=10.0.0.1:80",
+		"TestForkWith: quoted conn_id=42 peer=10.0.0.1:80 note=\"two words\"",
+		"TestForkWith: no fields",
+	}
+	if len(raw.lines) != len(expectedLines) {
+		t.Fatalf("Expected %d log lines; got %d: %q", len(expectedLines), len(raw.lines), raw.lines)
+	}
+	for i, expectedLine := range expectedLines {
+		if raw.lines[i] != expectedLine {
+			t.Errorf("Expected log line [%s]; got [%s]", expectedLine, raw.lines[i])
+		}
+	}
+
+	if n := len(conn.Fields()); n != 2 {
+		t.Errorf("Expected 2 fields on forked logger; got %d", n)
+	}
+	if n := len(lg.Fields()); n != 0 {
+		t.Errorf("Expected no fields on parent logger; got %d", n)
+	}
+}
+
+type nilPtrError struct{ msg string }
+
+func (e *nilPtrError) Error() string { return e.msg }
+
+type nilPtrStringer struct{ s string }
+
+func (x *nilPtrStringer) String() string { return x.s }
+
+type panicStringer struct{}
+
+func (panicStringer) String() string { panic("boom") }
+
+func TestTypedNilFieldValues(t *testing.T) {
+	raw := &captureRawLogger{}
+	lg := NewLogWrapper(raw, "TestTypedNilFieldValues", LogLevelDebug)
+
+	var err *nilPtrError
+	var str *nilPtrStringer
+	lg.ILogw("typed nil", "err", err, "str", str, "bad", panicStringer{})
+
+	expected := "TestTypedNilFieldValues: typed nil err=<nil> str=<nil> bad=\"%!v(PANIC=String method: boom)\""
+	if len(raw.lines) != 1 || raw.lines[0] != expected {
+		t.Errorf("Expected log line [%s]; got %q", expected, raw.lines)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	records := []*Record{}
+	h := HandlerFunc(func(r *Record) error {
+		records = append(records, r)
+		return nil
+	})
+
+	lg := NewWithHandler(h, "TestHandler", LogLevelInfo)
+	to := NewTestObj(lg, 2).ForkWith("k", "v")
+	to.WLogf("Log Message %d", 1)
+	to.DLog("filtered")
+	to.Print("unconditional")
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records; got %d", len(records))
+	}
+	r := records[0]
+	if r.Level != LogLevelWarning {
+		t.Errorf("Expected level %v; got %v", LogLevelWarning, r.Level)
+	}
+	if r.Prefix() != "TestHandler: TestObj 2" || len(r.PrefixPath) != 2 {
+		t.Errorf("Unexpected prefix path %q", r.PrefixPath)
+	}
+	if r.Message != "Log Message 1" {
+		t.Errorf("Unexpected message %q", r.Message)
+	}
+	if r.Text() != "TestHandler: TestObj 2: Log Message 1 k=v" {
+		t.Errorf("Unexpected text %q", r.Text())
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+	if filepath.Base(frame.File) != "logger_test.go" {
+		t.Errorf("Expected caller in logger_test.go; got %s:%d", frame.File, frame.Line)
+	}
+	if records[1].Level != LogLevelUnknown {
+		t.Errorf("Expected Print to emit LogLevelUnknown; got %v", records[1].Level)
+	}
+
+	var buf bytes.Buffer
+	lg = NewLogWrapper(log.New(&buf, "", log.Lshortfile), "TestHandler", LogLevelInfo)
+	NewTestObj(lg, 3).ILog("Log Message")
+	line := buf.String()
+	if !strings.HasPrefix(line, "logger_test.go:") || !strings.HasSuffix(line, ": TestHandler: TestObj 3: Log Message\n") {
+		t.Errorf("Unexpected raw logger output %q", line)
+	}
+}
+
+func TestLevelTag(t *testing.T) {
+	tests := []struct {
+		levelTag LevelTag
+		expected string
+	}{
+		{LevelTag{}, "TestLevelTag: TestObj 1: msg k=v"},
+		{LevelTag{Style: LevelTagBracketed}, "[WARNING] TestLevelTag: TestObj 1: msg k=v"},
+		{LevelTag{Style: LevelTagPlain, Short: true}, "W TestLevelTag: TestObj 1: msg k=v"},
+		{LevelTag{Style: LevelTagBracketed, Lowercase: true, Position: LevelTagAfterPrefix}, "TestLevelTag: TestObj 1: [warning] msg k=v"},
+		{LevelTag{Style: LevelTagSyslog, Position: LevelTagEnd}, "TestLevelTag: TestObj 1: msg k=v <4>"},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		lg, err := New(
+			WithWriter(&buf),
+			WithReplaceLogFlags(0),
+			WithPrefix("TestLevelTag"),
+			WithLevelTag(test.levelTag),
+		)
+		if err != nil {
+			t.Fatalf("logger.New() returned error: %s", err)
+		}
+		NewTestObj(lg, 1).WLogw("msg", "k", "v")
+		lg.Print("untagged")
+		expected := test.expected + "\nTestLevelTag: untagged\n"
+		if buf.String() != expected {
+			t.Errorf("With %+v, expected %q; got %q", test.levelTag, expected, buf.String())
+		}
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	lg, err := New(
+		WithWriter(&buf),
+		WithFormat(FormatJSON),
+		WithLUTC(),
+		WithLshortfile(),
+		WithPrefix("TestJSONFormat"),
+	)
+	if err != nil {
+		t.Fatalf("logger.New() returned error: %s", err)
+	}
+
+	NewTestObj(lg, 3).ForkWith("conn_id", 42).WLogw("line 1\n\"quoted\"", "msg", "collision", "ok", true)
+
+	var obj map[string]interface{}
+	err = json.Unmarshal(buf.Bytes(), &obj)
+	if err != nil {
+		t.Fatalf("json.Unmarshal(%q) returned error: %s", buf.String(), err)
+	}
+	expected := map[string]interface{}{
+		"level":       "warning",
+		"prefix":      "TestJSONFormat: TestObj 3",
+		"prefix_path": []interface{}{"TestJSONFormat", "TestObj 3"},
+		"msg":         "line 1\n\"quoted\"",
+		"conn_id":     float64(42),
+		"fields.msg":  "collision",
+		"ok":          true,
+	}
+	for k, v := range expected {
+		if fmt.Sprint(obj[k]) != fmt.Sprint(v) {
+			t.Errorf("Expected %s=%v; got %v", k, v, obj[k])
+		}
+	}
+	ts, _ := obj["time"].(string)
+	if !strings.HasSuffix(ts, "Z") || len(ts) != len("2006-01-02T15:04:05Z") {
+		t.Errorf("Unexpected time %q", ts)
+	}
+	caller, _ := obj["caller"].(string)
+	if !strings.HasPrefix(caller, "logger_test.go:") {
+		t.Errorf("Unexpected caller %q", caller)
+	}
+}
+
+func TestJSONTypedNilFieldValues(t *testing.T) {
+	var buf bytes.Buffer
+	lg, err := New(WithWriter(&buf), WithFormat(FormatJSON))
+	if err != nil {
+		t.Fatalf("logger.New() returned error: %s", err)
+	}
+
+	var nilErr *nilPtrError
+	var str *nilPtrStringer
+	lg.WLogw("typed nil", "err", nilErr, "str", str)
+
+	var obj map[string]interface{}
+	err = json.Unmarshal(buf.Bytes(), &obj)
+	if err != nil {
+		t.Fatalf("json.Unmarshal(%q) returned error: %s", buf.String(), err)
+	}
+	if obj["err"] != "<nil>" {
+		t.Errorf("Expected err=<nil>; got %v", obj["err"])
+	}
+	if v, ok := obj["str"]; !ok || v != nil {
+		t.Errorf("Expected str=null; got %v", v)
+	}
+}
+
+func TestLogfmtFormat(t *testing.T) {
+	var buf bytes.Buffer
+	lg, err := New(
+		WithWriter(&buf),
+		WithFormat(FormatLogfmt),
+		WithFormatOptions(FormatOptions{Caller: CallerShort}),
+		WithLogLevel(LogLevelInfo),
+	)
+	if err != nil {
+		t.Fatalf("logger.New() returned error: %s", err)
+	}
+
+	NewTestObj(lg, 3).ILogw("say \"hi\"\nthere", "empty", "", "a key", "x=y", "n", 5)
+	lg.Print("plain")
+
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) != 3 || lines[2] != "" {
+		t.Fatalf("Expected 2 lines; got %q", buf.String())
+	}
+	expectedHead := `level=info prefix="TestObj 3" msg="say \"hi\"\nthere" caller=logger_test.go:`
+	expectedTail := ` empty="" a_key="x=y" n=5`
+	if !strings.HasPrefix(lines[0], expectedHead) || !strings.HasSuffix(lines[0], expectedTail) {
+		t.Errorf("Expected [%s<line>%s]; got [%s]", expectedHead, expectedTail, lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "msg=plain caller=logger_test.go:") {
+		t.Errorf("Unexpected line [%s]", lines[1])
+	}
+}
+
+func TestConsoleFormat(t *testing.T) {
+	for _, colorMode := range []ColorMode{ColorNever, ColorAlways} {
+		var buf bytes.Buffer
+		lg, err := New(
+			WithWriter(&buf),
+			WithFormat(FormatConsole),
+			WithColor(colorMode),
+			WithReplaceLogFlags(0),
+			WithPrefix("TestConsoleFormat"),
+		)
+		if err != nil {
+			t.Fatalf("logger.New() returned error: %s", err)
+		}
+		NewTestObj(lg, 1).WLogw("careful", "k", "v")
+
+		line := buf.String()
+		hasColor := strings.Contains(line, "\x1b[")
+		if hasColor != (colorMode == ColorAlways) {
+			t.Errorf("With %s, unexpected color in %q", colorMode, line)
+		}
+		if colorMode == ColorNever && line != "WRN TestConsoleFormat: TestObj 1: careful k=v\n" {
+			t.Errorf("Unexpected console line %q", line)
+		}
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	f, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("os.Open(%q) returned error: %s", os.DevNull, err)
+	}
+	defer f.Close()
+	if isTerminal(f) {
+		t.Errorf("Expected %s not to be a terminal", os.DevNull)
+	}
+	if ColorAuto.colorEnabled(f) && os.Getenv("FORCE_COLOR") == "" {
+		t.Errorf("Expected no color for output to %s", os.DevNull)
+	}
+}
+
+func TestLevelVar(t *testing.T) {
+	var mu sync.Mutex
+	count := 0
+	h := HandlerFunc(func(r *Record) error {
+		mu.Lock()
+		count++
+		mu.Unlock()
+		return nil
+	})
+
+	lg := NewWithHandler(h, "TestLevelVar", LogLevelWarning)
+	to1 := NewTestObj(lg, 1)
+	to2 := NewTestObj(lg, 2)
+	to2.SetLogLevel(LogLevelError)
+
+	lg.SetLogLevel(LogLevelDebug)
+	if to1.GetLogLevel() != LogLevelDebug {
+		t.Errorf("Expected fork to inherit %v; got %v", LogLevelDebug, to1.GetLogLevel())
+	}
+	if to2.GetLogLevel() != LogLevelError {
+		t.Errorf("Expected fork with explicit level to keep %v; got %v", LogLevelError, to2.GetLogLevel())
+	}
+	to2.SetLogLevel(LogLevelUnknown)
+	if to2.GetLogLevel() != LogLevelDebug {
+		t.Errorf("Expected reset fork to inherit %v; got %v", LogLevelDebug, to2.GetLogLevel())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			to := NewTestObj(lg, i)
+			for j := 0; j < 100; j++ {
+				to.DLogf("Log Message %d", j)
+				if j%10 == 0 {
+					lg.SetLogLevel(LogLevelDebug)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if count != 400 {
+		t.Errorf("Expected 400 records; got %d", count)
+	}
+}
+
+func TestLevelRules(t *testing.T) {
+	records := []*Record{}
+	rules := NewLevelRules()
+	lg, err := New(
+		WithHandler(HandlerFunc(func(r *Record) error {
+			records = append(records, r)
+			return nil
+		})),
+		WithPrefix("TestLogging"),
+		WithLogLevel(LogLevelWarning),
+		WithLevelRules(rules),
+	)
+	if err != nil {
+		t.Fatalf("logger.New() returned error: %s", err)
+	}
+
+	to1 := NewTestObj(lg, 1)
+	to2 := NewTestObj(lg, 2)
+	conn := to2.ForkLogStr("conn 17")
+
+	to1.DLog("before")
+	to2.DLog("before")
+	rules.Set("TestLogging: TestObj 2", LogLevelDebug)
+	to1.DLog("after")
+	to2.DLog("after")
+	conn.DLog("inherited")
+	rules.Set("*: conn 17", LogLevelWarning)
+	conn.DLog("overridden")
+	rules.Remove("TestLogging: TestObj 2")
+	to2.DLog("removed")
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records; got %d", len(records))
+	}
+	if records[0].Text() != "TestLogging: TestObj 2: after" || records[1].Text() != "TestLogging: TestObj 2: conn 17: inherited" {
+		t.Errorf("Unexpected records %q, %q", records[0].Text(), records[1].Text())
+	}
+
+	// A rule matching the root prefix applies unless the level is set explicitly
+	records = nil
+	rules = NewLevelRules(LevelRule{Pattern: "TestLogging", Level: LogLevelDebug})
+	handler := HandlerFunc(func(r *Record) error {
+		records = append(records, r)
+		return nil
+	})
+	lg, err = New(WithHandler(handler), WithPrefix("TestLogging"), WithLevelRules(rules))
+	if err != nil {
+		t.Fatalf("logger.New() returned error: %s", err)
+	}
+	lg.DLog("root rule")
+	lg.SetLogLevel(LogLevelWarning)
+	lg.DLog("explicit")
+	lg, err = New(WithHandler(handler), WithPrefix("TestLogging"), WithLevelRules(rules), WithLogLevel(LogLevelWarning))
+	if err != nil {
+		t.Fatalf("logger.New() returned error: %s", err)
+	}
+	lg.DLog("explicit")
+	lg.ForkLogStr("child").DLog("default")
+	rules.Remove("TestLogging")
+	lg, err = New(WithHandler(handler), WithPrefix("TestLogging"), WithLevelRules(rules))
+	if err != nil {
+		t.Fatalf("logger.New() returned error: %s", err)
+	}
+	lg.DLog("default")
+	lg.WLog("default")
+	if len(records) != 2 || records[0].Message != "root rule" || records[1].Message != "default" ||
+		records[1].Level != LogLevelWarning {
+		t.Errorf("Unexpected records %v", records)
+	}
+
+	for _, test := range []struct {
+		pattern string
+		prefix  string
+		match   bool
+	}{
+		{"db:*", "db: conn", true},
+		{"db:*", "dbx: conn", false},
+		{"*: conn 17", "a: b: conn 17", true},
+		{"*: conn 17", "conn 17", false},
+		{"TestObj ?", "TestObj 3", true},
+		{"", "", true},
+	} {
+		if globMatch(test.pattern, test.prefix) != test.match {
+			t.Errorf("globMatch(%q, %q) returned %v", test.pattern, test.prefix, !test.match)
+		}
+	}
+}
+
+func TestEnv(t *testing.T) {
+	env := map[string]string{
+		"MYAPP_LOG_LEVEL":  "error",
+		"MYAPP_LOG_FORMAT": "logfmt",
+		"MYAPP_LOG_FLAGS":  "none,utc,micro,shortfile",
+		"MYAPP_LOG_SPEC":   "info,db=debug,http*=warning",
+	}
+	cfg := NewConfig()
+	cfg.applyEnv("MYAPP", func(name string) string { return env[name] })
+	if cfg.Err() != nil {
+		t.Fatalf("applyEnv returned error: %s", cfg.Err())
+	}
+	if cfg.logLevel != LogLevelInfo || cfg.format != FormatLogfmt || cfg.flag != log.LUTC|log.Lmicroseconds|log.Lshortfile {
+		t.Errorf("Unexpected config level=%v format=%v flag=%#x", cfg.logLevel, cfg.format, cfg.flag)
+	}
+	rules := cfg.specRules
+	if len(rules) != 2 || rules[0] != (LevelRule{"db", LogLevelDebug}) || rules[1] != (LevelRule{"http*", LogLevelWarning}) {
+		t.Errorf("Unexpected rules %+v", rules)
+	}
+	if !cfg.logLevelSet {
+		t.Errorf("Expected MYAPP_LOG_SPEC to set the log level explicitly")
+	}
+
+	// A table provided by WithLevelRules is consulted after the spec rules, whatever the option order, and
+	// is not changed, so later changes to it still take effect
+	for _, envFirst := range []bool{false, true} {
+		shared := NewLevelRules(LevelRule{"api", LogLevelError}, LevelRule{"db", LogLevelError})
+		opts := []ConfigOption{WithLevelRules(shared), WithWriter(io.Discard)}
+		envOpt := func(cfg *Config) { cfg.applyEnv("MYAPP", func(name string) string { return env[name] }) }
+		if envFirst {
+			opts = append([]ConfigOption{envOpt}, opts...)
+		} else {
+			opts = append(opts, envOpt)
+		}
+		lg, err := New(opts...)
+		if err != nil {
+			t.Fatalf("New() returned error: %s", err)
+		}
+		if len(shared.Rules()) != 2 {
+			t.Errorf("Shared rules changed to %+v", shared.Rules())
+		}
+		db := lg.ForkLogStr("db")
+		api := lg.ForkLogStr("api")
+		other := lg.ForkLogStr("other")
+		if db.GetLogLevel() != LogLevelDebug || api.GetLogLevel() != LogLevelError || other.GetLogLevel() != LogLevelInfo {
+			t.Errorf("envFirst=%v: unexpected levels db=%v api=%v other=%v", envFirst, db.GetLogLevel(), api.GetLogLevel(), other.GetLogLevel())
+		}
+		shared.Set("api", LogLevelTrace)
+		shared.Set("other", LogLevelWarning)
+		if api.GetLogLevel() != LogLevelTrace || other.GetLogLevel() != LogLevelWarning {
+			t.Errorf("envFirst=%v: changes to shared rules ignored: api=%v other=%v", envFirst, api.GetLogLevel(), other.GetLogLevel())
+		}
+	}
+
+	env["MYAPP_LOG_LEVEL"] = "loud"
+	env["MYAPP_LOG_FLAGS"] = "bogus"
+	cfg = NewConfig()
+	cfg.applyEnv("MYAPP", func(name string) string { return env[name] })
+	_, err := NewWithConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "MYAPP_LOG_LEVEL") || !strings.Contains(err.Error(), "MYAPP_LOG_FLAGS") {
+		t.Errorf("Expected errors for MYAPP_LOG_LEVEL and MYAPP_LOG_FLAGS; got %v", err)
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cf := RegisterFlags(fs, "")
+	err := fs.Parse([]string{"--log-level", "Debug", "--log-format=json", "--log-utc", "--log-no-date"})
+	if err != nil {
+		t.Fatalf("fs.Parse() returned error: %s", err)
+	}
+
+	cfg := NewConfig(WithLogLevel(LogLevelError), WithFormat(FormatLogfmt), WithColor(ColorNever), WithConfigFlags(cf))
+	if cfg.logLevel != LogLevelDebug || cfg.format != FormatJSON || cfg.colorMode != ColorNever || cfg.flag != log.Ltime|log.LUTC {
+		t.Errorf("Unexpected config level=%v format=%v color=%v flag=%#x", cfg.logLevel, cfg.format, cfg.colorMode, cfg.flag)
+	}
+
+	err = fs.Parse([]string{"--log-level", "loud"})
+	if err == nil {
+		t.Errorf("Expected error parsing invalid --log-level")
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	lg := NewLogWrapper(log.New(&buf, "", log.Lshortfile), "TestSlogHandler", LogLevelInfo)
+	sl := NewTestObj(lg, 1).Logger.(*BasicLogger).Slog()
+
+	sl.Debug("filtered")
+	sl.With("conn_id", 42).WithGroup("db").Info("query", "rows", 3, slog.Group("timing", "ms", 7))
+	sl.Warn("warned")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	expectedTails := []string{
+		": TestSlogHandler: TestObj 1: db: query conn_id=42 rows=3 timing.ms=7",
+		": TestSlogHandler: TestObj 1: warned",
+	}
+	if len(lines) != len(expectedTails) {
+		t.Fatalf("Expected %d lines; got %q", len(expectedTails), buf.String())
+	}
+	for i, tail := range expectedTails {
+		if !strings.HasPrefix(lines[i], "logger_test.go:") || !strings.HasSuffix(lines[i], tail) {
+			t.Errorf("Expected [logger_test.go:<line>%s]; got [%s]", tail, lines[i])
+		}
+	}
+
+	for _, logLevel := range []LogLevel{LogLevelError, LogLevelWarning, LogLevelInfo, LogLevelDebug, LogLevelTrace} {
+		if LogLevelFromSlog(LogLevelToSlog(logLevel)) != logLevel {
+			t.Errorf("Level %v did not survive conversion to and from slog", logLevel)
+		}
+	}
+}
+
+func TestFromSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	sh := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	lg := NewFromSlogHandler(sh, "svc", LogLevelDebug)
+
+	db := lg.ForkLogStr("db").ForkWith("conn_id", 42)
+	db.ILogf("opened %d", 3)
+	db.DLog("filtered by the slog handler")
+	lg.ELogw("failed", "err", "timeout")
+	func() {
+		defer func() { recover() }()
+		lg.Panic("boom")
+	}()
+
+	expectedLines := []string{
+		`level=INFO msg="opened 3" prefix="svc: db" conn_id=42`,
+		`level=ERROR msg=failed prefix=svc err=timeout`,
+		`level=ERROR+8 msg=boom prefix=svc`,
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(expectedLines) {
+		t.Fatalf("Expected %d lines; got %q", len(expectedLines), buf.String())
+	}
+	for i, expected := range expectedLines {
+		if lines[i] != expected {
+			t.Errorf("Expected [%s]; got [%s]", expected, lines[i])
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	lg := NewLogWrapper(log.New(&buf, "", log.Lshortfile), "TestWriter", LogLevelInfo)
+
+	w := lg.ForkWith("stream", "stderr").Writer(LogLevelWarning)
+	_, _, line, _ := runtime.Caller(0)
+	fmt.Fprint(w, "one\ntw")
+	fmt.Fprint(w, "o\r\nthree")
+	w.Close()
+	_, err := w.Write([]byte("closed\n"))
+	if err != os.ErrClosed {
+		t.Errorf("Expected os.ErrClosed writing to closed Writer; got %v", err)
+	}
+
+	errorLog := log.New(lg.Writer(LogLevelError), "", 0)
+	errorLog.Printf("via *log.Logger")
+
+	lg.Writer(LogLevelDebug).Write([]byte("filtered\n"))
+
+	restore := RedirectStdLog(lg.ForkLogStr("std"), LogLevelInfo)
+	log.Printf("from %s", "log.Printf")
+	restore()
+
+	expectedLines := []string{
+		fmt.Sprintf("logger_test.go:%d: TestWriter: one stream=stderr", line+1),
+		fmt.Sprintf("logger_test.go:%d: TestWriter: two stream=stderr", line+2),
+		fmt.Sprintf("logger_test.go:%d: TestWriter: three stream=stderr", line+3),
+		fmt.Sprintf("logger_test.go:%d: TestWriter: via *log.Logger", line+10),
+		fmt.Sprintf("logger_test.go:%d: TestWriter: std: from log.Printf", line+15),
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(expectedLines) {
+		t.Fatalf("Expected %d lines; got %q", len(expectedLines), buf.String())
+	}
+	for i, expected := range expectedLines {
+		if lines[i] != expected {
+			t.Errorf("Expected [%s]; got [%s]", expected, lines[i])
+		}
+	}
+	if log.Writer() != os.Stderr {
+		t.Errorf("Expected RedirectStdLog restore to restore the standard logger's output")
+	}
+}
+
+func TestWriterLongLinesAndReentry(t *testing.T) {
+	var messages []string
+	var w io.WriteCloser
+	lg := NewWithHandler(HandlerFunc(func(r *Record) error {
+		messages = append(messages, r.Message)
+		if r.Message == "outer" {
+			// A handler that writes back to the Writer must not deadlock
+			fmt.Fprintln(w, "inner")
+		}
+		return nil
+	}), "", LogLevelInfo)
+	w = lg.Writer(LogLevelInfo)
+
+	long := strings.Repeat("x", maxLineLength)
+	fmt.Fprint(w, long[:100])
+	fmt.Fprint(w, long[100:]+"yz")
+	fmt.Fprint(w, "\n")
+	fmt.Fprint(w, long+"\n")
+	fmt.Fprint(w, "outer\n")
+	w.Close()
+
+	expected := []string{long, "yz", long, "outer", "inner"}
+	if len(messages) != len(expected) {
+		t.Fatalf("Expected %d messages; got %d", len(expected), len(messages))
+	}
+	for i := range expected {
+		if messages[i] != expected[i] {
+			t.Errorf("Message %d: expected %d bytes; got %d bytes %.20q", i, len(expected[i]), len(messages[i]), messages[i])
+		}
+	}
+}
+
+// readSegments returns the lines in all segments of a RotatingFile, decompressing compressed segments
+func readSegments(t *testing.T, f *RotatingFile) []string {
+	segments, err := f.segments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, seg := range segments {
+		data, err := os.ReadFile(seg.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seg.compressed {
+			zr, err := gzip.NewReader(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			_, err = buf.ReadFrom(zr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data = buf.Bytes()
+		}
+		if len(data) > 0 {
+			lines = append(lines, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")...)
+		}
+	}
+	return lines
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	lg, err := New(WithRotatingFile(path, RotateOptions{MaxSize: 1000, Compress: true, Symlink: true}),
+		WithPrefix("TestRotatingFile"), WithReplaceLogFlags(0), WithLogLevel(LogLevelInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := lg.(*BasicLogger).handler.(*WriterHandler).w.(*RotatingFile)
+
+	const numGoroutines = 8
+	const numLines = 200
+	var wg sync.WaitGroup
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			to := NewTestObj(lg, i)
+			for j := 0; j < numLines; j++ {
+				to.ILogf("Log Message %d", j)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	target, err := os.Readlink(path)
+	if err != nil || filepath.Join(dir, target) != f.Name() {
+		t.Errorf("Expected symlink to current segment %s; got %s (%v)", f.Name(), target, err)
+	}
+	err = f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	segments, _ := f.segments()
+	for _, seg := range segments {
+		if seg.path != f.Name() && !seg.compressed {
+			t.Errorf("Expected rotated segment %s to be compressed", seg.path)
+		}
+	}
+	if len(segments) < numGoroutines*numLines*40/1000 {
+		t.Errorf("Expected output to be rotated into many segments; got %d", len(segments))
+	}
+
+	seen := map[string]int{}
+	for _, line := range readSegments(t, f) {
+		seen[line]++
+	}
+	for i := 0; i < numGoroutines; i++ {
+		for j := 0; j < numLines; j++ {
+			line := fmt.Sprintf("TestRotatingFile: TestObj %d: Log Message %d", i, j)
+			if seen[line] != 1 {
+				t.Errorf("Expected [%s] exactly once; got %d", line, seen[line])
+			}
+		}
+	}
+}
+
+func TestRotatingFileSameSecond(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	now := time.Date(2026, 10, 17, 14, 59, 0, 0, time.UTC)
+	f := &RotatingFile{
+		path: path,
+		opts: RotateOptions{Compress: true, UTC: true},
+		now:  func() time.Time { return now },
+	}
+	err := f.openExisting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotate several times within the same second, sometimes waiting for the rotated segment to be
+	// compressed before rotating again
+	const numRotations = 6
+	for i := 0; i < numRotations; i++ {
+		fmt.Fprintf(f, "line %d\n", i)
+		err = f.Rotate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i%2 == 0 {
+			f.bgWg.Wait()
+		}
+	}
+	fmt.Fprintf(f, "line %d\n", numRotations)
+	err = f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	segments, _ := f.segments()
+	compressed := 0
+	for _, seg := range segments {
+		if seg.compressed {
+			compressed++
+		}
+	}
+	if len(segments) != numRotations+1 || compressed != numRotations {
+		t.Errorf("Expected %d compressed segments and the current segment; got %v", numRotations, segments)
+	}
+	if lines := readSegments(t, f); strings.Join(lines, ",") != "line 0,line 1,line 2,line 3,line 4,line 5,line 6" {
+		t.Errorf("Unexpected segment contents %v", lines)
+	}
+
+	// Compression never replaces an existing compressed segment
+	name := filepath.Join(dir, "other.log")
+	err = os.WriteFile(name, []byte("new\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(name+".gz", []byte("old"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = compressFile(name)
+	if err == nil {
+		t.Errorf("Expected compressing over an existing compressed segment to fail")
+	}
+	if data, _ := os.ReadFile(name + ".gz"); string(data) != "old" {
+		t.Errorf("Expected existing compressed segment to be preserved; got %q", data)
+	}
+	if _, err := os.Stat(name); err != nil {
+		t.Errorf("Expected segment to be kept when compression fails: %v", err)
+	}
+}
+
+func TestRotatingFileInterval(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	now := time.Date(2026, 10, 17, 14, 59, 0, 0, time.UTC)
+	f := &RotatingFile{
+		path: path,
+		opts: RotateOptions{Interval: RotateHourly, MaxBackups: 2, UTC: true},
+		now:  func() time.Time { return now },
+	}
+	err := f.openExisting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 7; i++ {
+		fmt.Fprintf(f, "line %d\n", i)
+		now = now.Add(30 * time.Minute)
+	}
+	err = f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Segments were created at 14:59, 15:29, 16:29 and 17:29; the first was removed by MaxBackups
+	expectedNames := []string{"app-20261017T152900.log", "app-20261017T162900.log", "app-20261017T172900.log"}
+	segments, _ := f.segments()
+	names := []string{}
+	for _, seg := range segments {
+		names = append(names, filepath.Base(seg.path))
+	}
+	if strings.Join(names, " ") != strings.Join(expectedNames, " ") {
+		t.Errorf("Expected segments %v; got %v", expectedNames, names)
+	}
+	if lines := readSegments(t, f); strings.Join(lines, ",") != "line 1,line 2,line 3,line 4,line 5,line 6" {
+		t.Errorf("Unexpected segment contents %v", lines)
+	}
+
+	// Reopening in the same interval appends to the newest segment
+	now = time.Date(2026, 10, 17, 17, 45, 0, 0, time.UTC)
+	f2 := &RotatingFile{path: path, opts: f.opts, now: f.now}
+	err = f2.openExisting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f2.Name() != segments[len(segments)-1].path {
+		t.Errorf("Expected reopened RotatingFile to append to %s; got %s", segments[len(segments)-1].path, f2.Name())
+	}
+	f2.Close()
+}
+
+func TestReopenableFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	lg, err := New(WithReopenableLogFile(path), WithPrefix("TestReopenableFile"), WithReplaceLogFlags(0),
+		WithLogLevel(LogLevelInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := lg.(*BasicLogger).handler.(*WriterHandler).w.(*ReopenableFile)
+
+	const numGoroutines = 8
+	const numLines = 500
+	var wg sync.WaitGroup
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			to := NewTestObj(lg, i)
+			for j := 0; j < numLines; j++ {
+				to.ILogf("Log Message %d", j)
+			}
+		}(i)
+	}
+
+	// Rotate the file the way logrotate does while the goroutines are logging
+	done := make(chan struct{})
+	rotated := make(chan int)
+	go func() {
+		n := 0
+		defer func() { rotated <- n }()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			n++
+			err := os.Rename(path, fmt.Sprintf("%s.%d", path, n))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			err = f.Reopen()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	wg.Wait()
+	close(done)
+	n := <-rotated
+	err = f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.Write([]byte("after close\n"))
+	if err != os.ErrClosed {
+		t.Errorf("Expected os.ErrClosed after Close; got %v", err)
+	}
+
+	paths := []string{path}
+	for i := 1; i <= n; i++ {
+		paths = append(paths, fmt.Sprintf("%s.%d", path, i))
+	}
+	seen := map[string]int{}
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+			if line != "" {
+				seen[line]++
+			}
+		}
+	}
+	if len(seen) != numGoroutines*numLines {
+		t.Errorf("Expected %d distinct lines; got %d", numGoroutines*numLines, len(seen))
+	}
+	for i := 0; i < numGoroutines; i++ {
+		for j := 0; j < numLines; j++ {
+			line := fmt.Sprintf("TestReopenableFile: TestObj %d: Log Message %d", i, j)
+			if seen[line] != 1 {
+				t.Errorf("Expected [%s] exactly once; got %d", line, seen[line])
+			}
+		}
+	}
+}
+
+func TestReopenableFileSignal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	f, err := OpenReopenableFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	stop := f.ReopenOnSignal(syscall.SIGHUP)
+	defer stop()
+
+	fmt.Fprintln(f, "before")
+	err = os.Rename(path, path+".1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.Signal(syscall.SIGHUP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := os.Stat(path)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected file to be reopened after SIGHUP")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	fmt.Fprintln(f, "after")
+
+	for p, expected := range map[string]string{path + ".1": "before\n", path: "after\n"} {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != expected {
+			t.Errorf("Expected %s to contain %q; got %q", p, expected, data)
+		}
+	}
+
+	// A logger that opened the file is told when reopening fails
+	path = filepath.Join(dir, "sub", "app.log")
+	err = os.Mkdir(filepath.Dir(path), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg, err := New(WithReopenableLogFile(path, syscall.SIGHUP))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bl := lg.(*BasicLogger)
+	defer bl.handler.(*WriterHandler).w.(*ReopenableFile).Close()
+	err = os.RemoveAll(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.Signal(syscall.SIGHUP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline = time.Now().Add(5 * time.Second)
+	for bl.Stats().LastError == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the reopen error to be reported after SIGHUP")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	stats := bl.Stats()
+	if !strings.HasPrefix(stats.LastError.Error(), "unable to reopen ") || stats.BackgroundErrors != 1 {
+		t.Errorf("Unexpected error %v", stats.LastError)
+	}
+}
+
+// readOctetCounted reads a single octet-counted syslog frame
+func readOctetCounted(r *bufio.Reader) (string, error) {
+	n := 0
+	_, err := fmt.Fscanf(r, "%d ", &n)
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, n)
+	_, err = io.ReadFull(r, b)
+	return string(b), err
+}
+
+// testTLSConfigs returns a server tls.Config with a self-signed certificate for 127.0.0.1, and a client
+// tls.Config that trusts it
+func testTLSConfigs(t *testing.T) (*tls.Config, *tls.Config) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	serverConfig := &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	clientConfig := &tls.Config{RootCAs: pool}
+	return serverConfig, clientConfig
+}
+
+func TestSyslogSink(t *testing.T) {
+	tm := time.Date(2021, 6, 1, 12, 0, 0, 123456000, time.UTC)
+	r := &Record{
+		Time:       tm,
+		Level:      LogLevelWarning,
+		PrefixPath: []string{"db", "conn 3"},
+		Message:    "slow query",
+		Fields:     []Field{F("ms", 250), F("bad key", `a"b]c`)},
+	}
+	pid := os.Getpid()
+
+	// RFC 5424 over UDP
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	s, err := NewSyslogSink(SyslogOptions{Network: "udp", Addr: pc.LocalAddr().String(), Facility: SyslogFacilityLocal3,
+		AppName: "my app", Hostname: "host1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Handle(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4096)
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := fmt.Sprintf(`<156>1 2021-06-01T12:00:00.123456Z host1 my_app %d - [logger@32473 prefix="db: conn 3" ms="250" bad_key="a\"b\]c"] slow query`, pid)
+	if string(buf[:n]) != expected {
+		t.Errorf("Expected [%s]; got [%s]", expected, buf[:n])
+	}
+	s.Close()
+	err = s.Handle(r)
+	if err != os.ErrClosed {
+		t.Errorf("Expected os.ErrClosed after Close; got %v", err)
+	}
+
+	// RFC 3164 over a unix datagram socket, using the local daemon search
+	dir := t.TempDir()
+	sockPath := filepath.Join(dir, "log")
+	uc, err := net.ListenPacket("unixgram", sockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer uc.Close()
+	s, err = NewSyslogSink(SyslogOptions{Addr: sockPath, Format: SyslogRFC3164, AppName: "app", Hostname: "host1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	lg := NewWithHandler(s, "TestSyslogSink", LogLevelInfo)
+	lg.ELogf("disk full")
+	lg.DLogf("not sent")
+	uc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err = uc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = fmt.Sprintf(" host1 app[%d]: TestSyslogSink: disk full", pid)
+	if !strings.HasPrefix(string(buf[:n]), "<11>") || !strings.HasSuffix(string(buf[:n]), expected) {
+		t.Errorf("Expected [<11>... %s]; got [%s]", expected, buf[:n])
+	}
+
+	for _, useTLS := range []bool{false, true} {
+		testSyslogStream(t, r, useTLS)
+	}
+
+	// A failed reconnection is not retried until syslogReconnectDelay has passed
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	s, err = NewSyslogSink(SyslogOptions{Network: "tcp", Addr: addr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	ln.Close()
+	s.mu.Lock()
+	s.closeConn()
+	s.mu.Unlock()
+	dialErr := s.Handle(r)
+	if dialErr == nil {
+		t.Fatal("Expected an error with no server")
+	}
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	err = s.Handle(r)
+	if err != dialErr {
+		t.Errorf("Expected the previous error %v; got %v", dialErr, err)
+	}
+	s.mu.Lock()
+	s.retryAt = time.Time{}
+	s.mu.Unlock()
+	err = s.Handle(r)
+	if err != nil {
+		t.Errorf("Expected reconnection after the delay; got %v", err)
+	}
+}
+
+// testSyslogStream tests octet-counted framing and reconnection over TCP, with or without TLS
+func testSyslogStream(t *testing.T, r *Record, useTLS bool) {
+	var ln net.Listener
+	var err error
+	opts := SyslogOptions{Network: "tcp", AppName: "app", Hostname: "host1"}
+	if useTLS {
+		serverConfig, clientConfig := testTLSConfigs(t)
+		ln, err = tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+		opts.TLSConfig = clientConfig
+	} else {
+		ln, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	opts.Addr = ln.Addr().String()
+
+	// The server reads two messages from each connection, and then closes it
+	messages := make(chan string)
+	closed := make(chan struct{})
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			br := bufio.NewReader(conn)
+			for i := 0; i < 2; i++ {
+				msg, err := readOctetCounted(br)
+				if err != nil {
+					t.Error(err)
+					break
+				}
+				messages <- msg
+			}
+			conn.Close()
+			closed <- struct{}{}
+		}
+	}()
+
+	s, err := NewSyslogSink(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	for i := 0; i < 4; i++ {
+		r.Message = fmt.Sprintf("message\n%d", i)
+		err = s.Handle(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case msg := <-messages:
+			if !strings.HasSuffix(msg, "] "+r.Message) {
+				t.Errorf("Expected message ending with [%s]; got [%s]", r.Message, msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for message %d (TLS %v)", i, useTLS)
+		}
+		if i%2 == 1 {
+			<-closed
+		}
+	}
+}
+
+// gatedHandler is a Handler that records the messages of records, and blocks until it is released
+type gatedHandler struct {
+	started  chan string
+	release  chan struct{}
+	mu       sync.Mutex
+	messages []string
+}
+
+func newGatedHandler() *gatedHandler {
+	return &gatedHandler{started: make(chan string, 100), release: make(chan struct{})}
+}
+
+func (h *gatedHandler) Handle(r *Record) error {
+	h.started <- r.Message
+	<-h.release
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append(h.messages, r.Message)
+	return nil
+}
+
+func (h *gatedHandler) Messages() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return strings.Join(h.messages, ",")
+}
+
+func TestAsyncHandler(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		policy   AsyncPolicy
+		levels   []LogLevel
+		expected string
+		dropped  uint64
+	}{
+		{AsyncDropNewest, []LogLevel{LogLevelInfo, LogLevelInfo, LogLevelInfo, LogLevelInfo, LogLevelInfo}, "0,1,2", 2},
+		{AsyncDropOldest, []LogLevel{LogLevelInfo, LogLevelInfo, LogLevelInfo, LogLevelInfo, LogLevelInfo}, "0,3,4", 2},
+		{AsyncDropBelowLevel, []LogLevel{LogLevelInfo, LogLevelInfo, LogLevelInfo, LogLevelInfo, LogLevelError}, "0,1,2,4", 1},
+		{AsyncBlock, []LogLevel{LogLevelInfo, LogLevelInfo, LogLevelInfo, LogLevelDebug, LogLevelInfo}, "0,1,2,3,4", 0},
+	} {
+		gh := newGatedHandler()
+		h := NewAsyncHandler(gh, AsyncOptions{QueueSize: 2, Policy: tc.policy, DropLevel: LogLevelWarning})
+		lg := NewWithHandler(h, "", LogLevelTrace)
+
+		// Wait for the first record to be taken by the background goroutine, so that the queue fills
+		// deterministically
+		lg.Log(tc.levels[0], "0")
+		<-gh.started
+		done := make(chan struct{})
+		go func() {
+			for i := 1; i < len(tc.levels); i++ {
+				lg.Log(tc.levels[i], fmt.Sprint(i))
+			}
+			close(done)
+		}()
+		if tc.policy == AsyncDropNewest || tc.policy == AsyncDropOldest {
+			<-done
+		}
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		err := h.Flush(timeoutCtx)
+		cancel()
+		if err != context.DeadlineExceeded {
+			t.Errorf("Expected Flush of a blocked handler to time out; got %v", err)
+		}
+
+		close(gh.release)
+		<-done
+		err = lg.(*BasicLogger).Close(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gh.Messages() != tc.expected {
+			t.Errorf("Policy %d: expected records %s; got %s", tc.policy, tc.expected, gh.Messages())
+		}
+		if h.Dropped() != tc.dropped {
+			t.Errorf("Policy %d: expected %d dropped records; got %d", tc.policy, tc.dropped, h.Dropped())
+		}
+		err = h.Handle(&Record{Level: LogLevelInfo, Message: "late"})
+		if err != os.ErrClosed {
+			t.Errorf("Expected os.ErrClosed after Close; got %v", err)
+		}
+	}
+
+	// Fatal records are written before Handle returns, and are not dropped
+	gh := newGatedHandler()
+	close(gh.release)
+	h := NewAsyncHandler(gh, AsyncOptions{QueueSize: 1, Policy: AsyncDropNewest})
+	for i := 0; i < 20; i++ {
+		h.Handle(&Record{Level: LogLevelFatal, Message: "fatal"})
+		if len(gh.Messages()) != i*6+5 {
+			t.Fatalf("Expected %d fatal records to be written; got %s", i+1, gh.Messages())
+		}
+	}
+
+	// Flush waits only for records queued before it was called, and fatal records wait only for themselves, even
+	// while other goroutines keep logging. Fatal records are not dropped by AsyncDropOldest.
+	for _, policy := range []AsyncPolicy{AsyncBlock, AsyncDropOldest} {
+		var mu sync.Mutex
+		written := map[string]bool{}
+		h = NewAsyncHandler(HandlerFunc(func(r *Record) error {
+			time.Sleep(10 * time.Microsecond)
+			mu.Lock()
+			defer mu.Unlock()
+			written[r.Message] = true
+			return nil
+		}), AsyncOptions{QueueSize: 1, Policy: policy})
+		isWritten := func(msg string) bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return written[msg]
+		}
+		stop := make(chan struct{})
+		var wg sync.WaitGroup
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+						h.Handle(&Record{Level: LogLevelInfo, Message: "info"})
+					}
+				}
+			}()
+		}
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					msg := fmt.Sprintf("fatal %d %d", g, i)
+					h.Handle(&Record{Level: LogLevelFatal, Message: msg})
+					if !isWritten(msg) {
+						t.Errorf("Policy %d: expected %s to be written before Handle returned", policy, msg)
+					}
+				}
+			}(g)
+		}
+		for i := 0; i < 20; i++ {
+			msg := fmt.Sprintf("flushed %d", i)
+			h.Handle(&Record{Level: LogLevelError, Message: msg})
+			timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			err := h.Flush(timeoutCtx)
+			cancel()
+			if err != nil {
+				t.Fatalf("Policy %d: Flush while logging failed: %s", policy, err)
+			}
+			if policy == AsyncBlock && !isWritten(msg) {
+				t.Errorf("Policy %d: expected %s to be written before Flush returned", policy, msg)
+			}
+		}
+		close(stop)
+		wg.Wait()
+		h.Close(ctx)
+	}
+
+	// WithAsync wraps the configured destination
+	var buf bytes.Buffer
+	lg, err := New(WithAsync(AsyncOptions{}), WithWriter(&buf), WithPrefix("TestAsyncHandler"), WithReplaceLogFlags(0),
+		WithLogLevel(LogLevelInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		lg.ILogf("Log Message %d", i)
+	}
+	err = Flush(lg, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(buf.String(), "\n") != 100 || !strings.HasSuffix(buf.String(), "TestAsyncHandler: Log Message 99\n") {
+		t.Errorf("Expected 100 lines after Flush; got [%s]", buf.String())
+	}
+	Close(lg, ctx)
+
+	// Records dropped by WithAsync are counted in the logger's Stats
+	gh = newGatedHandler()
+	lg, err = New(WithHandler(gh), WithAsync(AsyncOptions{QueueSize: 1, Policy: AsyncDropNewest}), WithLogLevel(LogLevelInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.ILog("0")
+	<-gh.started
+	for i := 1; i < 4; i++ {
+		lg.ILog(fmt.Sprint(i))
+	}
+	close(gh.release)
+	err = Close(lg, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := lg.(*BasicLogger).Stats()
+	if gh.Messages() != "0,1" || stats.Dropped != 2 || stats.Records != 4 {
+		t.Errorf("Expected records 0,1 with 2 dropped; got %s with %+v", gh.Messages(), stats)
+	}
+}
+
+func TestTeeHandler(t *testing.T) {
+	var jsonBuf, textBuf, rawBuf bytes.Buffer
+	var mu sync.Mutex
+	var errs []error
+	failing := HandlerFunc(func(r *Record) error {
+		return fmt.Errorf("disk full")
+	})
+	panicking := HandlerFunc(func(r *Record) error {
+		panic("broken")
+	})
+	lg, err := New(WithTee(
+		TeeDestination{Handler: failing},
+		TeeDestination{Handler: NewWriterHandler(&jsonBuf, NewJSONFormatter(FormatOptions{})), Level: LogLevelDebug},
+		TeeDestination{Handler: panicking, Level: LogLevelWarning},
+		TeeDestination{Handler: NewWriterHandler(&textBuf, NewTextFormatter(FormatOptions{}, LevelTag{Style: LevelTagBracketed})), Level: LogLevelWarning},
+		TeeDestination{Handler: NewRawLoggerHandler(log.New(&rawBuf, "", log.Lshortfile)), Level: LogLevelError},
+	), WithPrefix("TestTeeHandler"), WithLogLevel(LogLevelTrace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lg.GetLogLevel() != LogLevelTrace {
+		t.Errorf("Expected level trace with an unrestricted destination; got %d", lg.GetLogLevel())
+	}
+
+	h := lg.(*BasicLogger).handler.(*TeeHandler)
+	record := func(logLevel LogLevel, msg string) {
+		err := h.Handle(&Record{Level: logLevel, PrefixPath: []string{"TestTeeHandler"}, Message: msg})
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}
+	record(LogLevelTrace, "trace")
+	record(LogLevelDebug, "debug")
+	record(LogLevelWarning, "warning")
+	_, _, line, _ := runtime.Caller(0)
+	lg.ELogf("error")
+
+	expectedJSON := `{"level":"debug","prefix":"TestTeeHandler","prefix_path":["TestTeeHandler"],"msg":"debug"}` + "\n" +
+		`{"level":"warning","prefix":"TestTeeHandler","prefix_path":["TestTeeHandler"],"msg":"warning"}` + "\n"
+	if !strings.HasPrefix(jsonBuf.String(), expectedJSON) || strings.Count(jsonBuf.String(), "\n") != 3 {
+		t.Errorf("Expected JSON destination to receive debug and above; got [%s]", jsonBuf.String())
+	}
+	expectedText := "[WARNING] TestTeeHandler: warning\n[ERROR] TestTeeHandler: error\n"
+	if textBuf.String() != expectedText {
+		t.Errorf("Expected [%s]; got [%s]", expectedText, textBuf.String())
+	}
+	expectedRaw := fmt.Sprintf("logger_test.go:%d: TestTeeHandler: error\n", line+1)
+	if rawBuf.String() != expectedRaw {
+		t.Errorf("Expected [%s]; got [%s]", expectedRaw, rawBuf.String())
+	}
+
+	if len(errs) != 3 || errs[0] == nil || errs[0].Error() != "disk full" {
+		t.Fatalf("Expected the failing destination's error; got %v", errs)
+	}
+	expectedErr := "disk full\nHandler panicked: broken"
+	if errs[2] == nil || errs[2].Error() != expectedErr {
+		t.Errorf("Expected [%s]; got [%v]", expectedErr, errs[2])
+	}
+
+	lg, err = New(WithTee(
+		TeeDestination{Handler: NewWriterHandler(&textBuf, NewTextFormatter(FormatOptions{}, LevelTag{})), Level: LogLevelWarning},
+		TeeDestination{Handler: NewRawLoggerHandler(NilLogger), Level: LogLevelDebug},
+	), WithLogLevel(LogLevelTrace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lg.GetLogLevel() != LogLevelWarning {
+		t.Errorf("Expected level limited to the destinations' levels; got %d", lg.GetLogLevel())
+	}
+
+	// Destinations with a Writer each have their own format, with the logger's format options
+	jsonBuf.Reset()
+	textBuf.Reset()
+	lg, err = New(WithTee(
+		TeeDestination{Writer: &jsonBuf, Format: FormatJSON, Level: LogLevelDebug},
+		TeeDestination{Writer: &textBuf, Format: FormatLogfmt, Level: LogLevelWarning},
+	), WithPrefix("TestTeeHandler"), WithLogLevel(LogLevelTrace), WithReplaceLogFlags(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.DLogw("debug", "n", 1)
+	lg.WLogw("warning", "n", 2)
+	expectedJSON = `{"level":"debug","prefix":"TestTeeHandler","prefix_path":["TestTeeHandler"],"msg":"debug","n":1}` + "\n" +
+		`{"level":"warning","prefix":"TestTeeHandler","prefix_path":["TestTeeHandler"],"msg":"warning","n":2}` + "\n"
+	if jsonBuf.String() != expectedJSON {
+		t.Errorf("Expected [%s]; got [%s]", expectedJSON, jsonBuf.String())
+	}
+	expectedText = "level=warning prefix=TestTeeHandler msg=warning n=2\n"
+	if textBuf.String() != expectedText {
+		t.Errorf("Expected [%s]; got [%s]", expectedText, textBuf.String())
+	}
+	_, err = New(WithTee(TeeDestination{Writer: &textBuf, Format: Format(99)}))
+	if err == nil {
+		t.Errorf("Expected an error for an unknown destination format")
+	}
+
+	// A destination with neither a Handler nor a Writer is rejected
+	empty := TeeDestination{Level: LogLevelError}
+	_, err = New(WithTee(TeeDestination{Writer: &textBuf}, empty))
+	if err == nil || !strings.Contains(err.Error(), "neither a Handler nor a Writer") {
+		t.Errorf("Expected an error for an empty destination; got %v", err)
+	}
+	th, err := NewTeeHandler(empty)
+	if err == nil {
+		t.Errorf("Expected NewTeeHandler to return an error for an empty destination")
+	}
+	_, err = New(WithHandler(th))
+	if err == nil {
+		t.Errorf("Expected New to return an error for a TeeHandler with an empty destination")
+	}
+	err = th.Handle(&Record{Level: LogLevelError, Message: "lost"})
+	if err == nil || strings.Contains(err.Error(), "panicked") {
+		t.Errorf("Expected the empty destination's error; got %v", err)
+	}
+}
+
+// flakyWriter is an io.Writer that fails a given number of times before writing to a buffer
+type flakyWriter struct {
+	failures int
+	buf      bytes.Buffer
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if w.failures != 0 {
+		w.failures--
+		return 0, fmt.Errorf("disk full")
+	}
+	return w.buf.Write(p)
+}
+
+func TestErrorHandler(t *testing.T) {
+	// By default, failures are counted and the error is returned by Output
+	w := &flakyWriter{failures: -1}
+	lg, err := New(WithWriter(w), WithPrefix("TestErrorHandler"), WithReplaceLogFlags(0), WithLogLevel(LogLevelInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bl := lg.(*BasicLogger)
+	if bl.Stats().LastError != nil {
+		t.Errorf("Expected no error before logging; got %v", bl.Stats().LastError)
+	}
+	start := time.Now()
+	lg.ILogf("lost")
+	lg.ForkLogStr("child").ELogf("lost")
+	err = lg.Output(1, "lost")
+	if err == nil || err.Error() != "disk full" {
+		t.Errorf("Expected Output to return the Handler's error; got %v", err)
+	}
+	stats := bl.Stats()
+	if stats.Records != 3 || stats.Failures != 3 || stats.Lost != 3 || stats.LastError == nil ||
+		stats.LastError.Error() != "disk full" || stats.LastErrorTime.Before(start) {
+		t.Errorf("Expected 3 failed records; got %+v", stats)
+	}
+
+	// FallbackErrorHandler writes the record elsewhere
+	var fallback bytes.Buffer
+	w = &flakyWriter{failures: 1}
+	lg, err = New(WithWriter(w), WithErrorHandler(FallbackErrorHandler(&fallback)), WithPrefix("TestErrorHandler"),
+		WithReplaceLogFlags(0), WithLogLevel(LogLevelInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.ELogf("first")
+	lg.ELogf("second")
+	if !strings.HasPrefix(fallback.String(), "logger: unable to write log record: disk full\n") ||
+		!strings.HasSuffix(fallback.String(), " [ERROR] TestErrorHandler: first\n") {
+		t.Errorf("Expected the first record on the fallback writer; got [%s]", fallback.String())
+	}
+	if w.buf.String() != "TestErrorHandler: second\n" {
+		t.Errorf("Expected the second record on the writer; got [%s]", w.buf.String())
+	}
+	stats = lg.(*BasicLogger).Stats()
+	if stats.Records != 2 || stats.Failures != 1 || stats.Lost != 0 {
+		t.Errorf("Expected 1 recovered failure; got %+v", stats)
+	}
+
+	// RetryErrorHandler handles the record again, with the correct caller
+	w = &flakyWriter{failures: 2}
+	lg, err = New(WithLogger(log.New(w, "", log.Lshortfile)), WithErrorHandler(RetryErrorHandler(3, time.Millisecond, nil)),
+		WithPrefix("TestErrorHandler"), WithLogLevel(LogLevelInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, line, _ := runtime.Caller(0)
+	lg.ELogf("retried")
+	expected := fmt.Sprintf("logger_test.go:%d: TestErrorHandler: retried\n", line+1)
+	if w.buf.String() != expected {
+		t.Errorf("Expected [%s]; got [%s]", expected, w.buf.String())
+	}
+	stats = lg.(*BasicLogger).Stats()
+	if stats.Records != 1 || stats.Failures != 1 || stats.Lost != 0 {
+		t.Errorf("Expected 1 recovered failure; got %+v", stats)
+	}
+
+	// A record is lost if every attempt fails
+	w = &flakyWriter{failures: 10}
+	lg, err = New(WithWriter(w), WithErrorHandler(RetryErrorHandler(2, time.Millisecond, nil)), WithLogLevel(LogLevelInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.ELogf("lost")
+	stats = lg.(*BasicLogger).Stats()
+	if stats.Lost != 1 || w.failures != 7 {
+		t.Errorf("Expected 1 lost record after 3 attempts; got %+v with %d failures remaining", stats, w.failures)
+	}
+
+	// Failures on the background goroutine of WithAsync are reported to the logger
+	fallback.Reset()
+	w = &flakyWriter{failures: -1}
+	lg, err = New(WithAsync(AsyncOptions{}), WithWriter(w), WithErrorHandler(FallbackErrorHandler(&fallback)),
+		WithPrefix("TestErrorHandler"), WithReplaceLogFlags(0), WithLogLevel(LogLevelInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bl = lg.(*BasicLogger)
+	lg.ILogf("async 1")
+	lg.ILogf("async 2")
+	err = bl.Close(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats = bl.Stats()
+	if stats.Records != 2 || stats.Failures != 2 || stats.Lost != 0 || stats.LastError == nil ||
+		stats.LastError.Error() != "disk full" {
+		t.Errorf("Expected 2 recovered failures; got %+v", stats)
+	}
+	if strings.Count(fallback.String(), "logger: unable to write log record: disk full\n") != 2 ||
+		!strings.HasSuffix(fallback.String(), " [INFO] TestErrorHandler: async 2\n") {
+		t.Errorf("Expected both records on the fallback writer; got [%s]", fallback.String())
+	}
+
+	// Background failures of a log file are counted by the logger, but are not passed to the ErrorHandler
+	fallback.Reset()
+	lg, err = New(WithRotatingFile(filepath.Join(t.TempDir(), "app.log"), RotateOptions{Compress: true}),
+		WithErrorHandler(FallbackErrorHandler(&fallback)), WithLogLevel(LogLevelInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bl = lg.(*BasicLogger)
+	rf := bl.handler.(*WriterHandler).w.(*RotatingFile)
+	defer rf.Close()
+	rf.postRotate(filepath.Join(t.TempDir(), "missing.log"))
+	stats = bl.Stats()
+	if stats.LastError == nil || !strings.HasPrefix(stats.LastError.Error(), "unable to compress ") ||
+		stats.BackgroundErrors != 1 || stats.Failures != 0 || fallback.Len() != 0 {
+		t.Errorf("Expected the compression error to be counted; got %+v, [%s]", stats, fallback.String())
+	}
+}
@@ -0,0 +1,22 @@
+//go:build ignore
+
+// gen.go generates mock_gen.go from the logger.Logger interface. Run it with go generate.
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/sammck-go/logger/loggermock/internal/mockgen"
+)
+
+func main() {
+	src, err := mockgen.Generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = os.WriteFile("mock_gen.go", src, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Package mockgen generates the source of loggermock.Mock from the method set of the logger.Logger interface,
+using reflection.
+*/
+package mockgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/sammck-go/logger"
+)
+
+var (
+	logLevelType = reflect.TypeOf(logger.LogLevel(0))
+	loggerType   = reflect.TypeOf((*logger.Logger)(nil)).Elem()
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Generate returns the formatted source of the generated part of package loggermock
+func Generate() ([]byte, error) {
+	g := &generator{imports: map[string]bool{loggerType.PkgPath(): true}}
+	g.generate(loggerType)
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by gen.go; DO NOT EDIT.\n\npackage loggermock\n\n")
+	if len(g.imports) > 0 {
+		paths := make([]string, 0, len(g.imports))
+		for path := range g.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		src.WriteString("import (\n")
+		for _, path := range paths {
+			fmt.Fprintf(&src, "\t%q\n", path)
+		}
+		src.WriteString(")\n\n")
+	}
+	src.Write(g.buf.Bytes())
+	return format.Source(src.Bytes())
+}
+
+// generator accumulates generated declarations and the imports that they require
+type generator struct {
+	buf     bytes.Buffer
+	imports map[string]bool
+}
+
+// printf appends formatted text to the generated declarations
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// generate generates the Funcs struct and a Mock method for each method of an interface type
+func (g *generator) generate(iface reflect.Type) {
+	g.printf("// Funcs holds optional replacements for the methods of a Mock. If a field is set, the\n")
+	g.printf("// corresponding method calls it after recording the call, rather than calling the base Logger.\n")
+	g.printf("type Funcs struct {\n")
+	for i := 0; i < iface.NumMethod(); i++ {
+		method := iface.Method(i)
+		g.printf("\t%s func%s\n", method.Name, g.signature(method.Type))
+	}
+	g.printf("}\n")
+
+	for i := 0; i < iface.NumMethod(); i++ {
+		g.generateMethod(iface.Method(i))
+	}
+}
+
+// signature returns the parameter and result lists of a method type, with parameters named arg0, arg1, etc.
+// and results named r0, r1, etc.
+func (g *generator) signature(mt reflect.Type) string {
+	params := make([]string, mt.NumIn())
+	for i := range params {
+		params[i] = fmt.Sprintf("arg%d %s", i, g.typeString(mt.In(i), mt.IsVariadic() && i == mt.NumIn()-1))
+	}
+	results := make([]string, mt.NumOut())
+	for i := range results {
+		results[i] = fmt.Sprintf("r%d %s", i, g.typeString(mt.Out(i), false))
+	}
+	s := "(" + strings.Join(params, ", ") + ")"
+	if len(results) > 0 {
+		s += " (" + strings.Join(results, ", ") + ")"
+	}
+	return s
+}
+
+// typeString returns the Go source for a type, recording any import that it requires. If variadic is
+// true, the type must be a slice, and it is rendered as a variadic parameter type.
+func (g *generator) typeString(t reflect.Type, variadic bool) string {
+	if variadic {
+		return "..." + g.typeString(t.Elem(), false)
+	}
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+		g.imports[t.PkgPath()] = true
+		return t.String()
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return "[]" + g.typeString(t.Elem(), false)
+	case reflect.Ptr:
+		return "*" + g.typeString(t.Elem(), false)
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "interface{}"
+		}
+	}
+	panic(fmt.Sprintf("mockgen: unsupported type %s", t))
+}
+
+// generateMethod generates a Mock method that records the call, calls the replacement function if it is
+// set, intercepts calls that would exit or panic if the Mock is configured to do so, and otherwise calls
+// the base Logger
+func (g *generator) generateMethod(method reflect.Method) {
+	mt := method.Type
+	name := method.Name
+	args := make([]string, mt.NumIn())
+	callArgs := make([]string, mt.NumIn())
+	for i := range args {
+		args[i] = fmt.Sprintf("arg%d", i)
+		callArgs[i] = args[i]
+	}
+	if mt.IsVariadic() {
+		callArgs[len(callArgs)-1] += "..."
+	}
+	baseArgs := append([]string(nil), callArgs...)
+	if strings.HasPrefix(name, "Cd") && mt.NumIn() > 0 && mt.In(0).Kind() == reflect.Int {
+		// The Mock adds a stack frame between the caller and the base Logger
+		baseArgs[0] = "arg0+1"
+	}
+
+	g.printf("\n// %s records the call, then calls Funcs.%s if it is set, or the base Logger's %s otherwise\n", name, name, name)
+	g.printf("func (m *Mock) %s%s {\n", name, g.signature(mt))
+	cond := interceptCondition(method)
+	if cond != "" {
+		g.printf("\tc := m.record(%q, []interface{}{%s})\n", name, strings.Join(args, ", "))
+	} else {
+		g.printf("\tm.record(%q, []interface{}{%s})\n", name, strings.Join(args, ", "))
+	}
+	if mt.NumOut() == 0 {
+		g.printf("\tif fn := m.Funcs.%s; fn != nil {\n\t\tfn(%s)\n\t\treturn\n\t}\n", name, strings.Join(callArgs, ", "))
+	} else {
+		g.printf("\tif fn := m.Funcs.%s; fn != nil {\n\t\treturn fn(%s)\n\t}\n", name, strings.Join(callArgs, ", "))
+	}
+
+	if cond != "" {
+		g.printf("\tif %s {\n\t\treturn\n\t}\n", cond)
+	}
+
+	baseCall := fmt.Sprintf("m.base.%s(%s)", name, strings.Join(baseArgs, ", "))
+	switch {
+	case mt.NumOut() == 0:
+		g.printf("\t%s\n", baseCall)
+	case mt.NumOut() == 1 && mt.Out(0) == loggerType:
+		g.printf("\treturn m.fork(%s)\n", baseCall)
+	default:
+		g.printf("\treturn %s\n", baseCall)
+	}
+	g.printf("}\n")
+}
+
+// interceptCondition returns an expression that calls m.intercept and is true if a call was intercepted, or
+// an empty string if the method can never exit or panic
+func interceptCondition(method reflect.Method) string {
+	mt := method.Type
+	if strings.HasPrefix(method.Name, "Log") || strings.HasPrefix(method.Name, "CdLog") {
+		for i := 0; i < mt.NumIn(); i++ {
+			if mt.In(i) == logLevelType {
+				return fmt.Sprintf("m.intercept(c, arg%d)", i)
+			}
+		}
+	}
+	switch {
+	case strings.HasPrefix(method.Name, "Fatal"):
+		return "m.intercept(c, logger.LogLevelFatal)"
+	case strings.HasSuffix(method.Name, "PanicOnError"):
+		for i := 0; i < mt.NumIn(); i++ {
+			if mt.In(i) == errorType {
+				return fmt.Sprintf("arg%d != nil && m.intercept(c, logger.LogLevelPanic)", i)
+			}
+		}
+	case strings.HasPrefix(method.Name, "Panic") || strings.HasPrefix(method.Name, "CdPanic"):
+		return "m.intercept(c, logger.LogLevelPanic)"
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+/*
+Package loggermock provides Mock, a logger.Logger that records every method call with its arguments, so that
+tests can verify how a component uses its Logger. Individual methods can be replaced with Funcs, and the
+exit and panic behavior of Fatal, Panic, etc. can be replaced with OnFatal and OnPanic.
+
+The methods of Mock are generated from the logger.Logger interface by gen.go; run go generate after changing
+the interface.
+*/
+package loggermock
+
+//go:generate go run gen.go
+
+import (
+	"sync"
+
+	"github.com/sammck-go/logger"
+)
+
+// Call is a single recorded method call
+type Call struct {
+	// Method is the name of the method that was called
+	Method string
+	// Prefix is the prefix of the Mock (or fork of a Mock) on which the method was called
+	Prefix string
+	// Args contains the arguments of the call. A variadic argument list is recorded as a single slice.
+	Args []interface{}
+}
+
+// Controls holds the configuration and recorded calls of a Mock, which are shared by all Mocks forked from it
+type Controls struct {
+	// Funcs holds optional replacements for individual methods
+	Funcs Funcs
+
+	// OnFatal, if set, is called instead of the base Logger for a call that would exit the process, such as Fatal
+	// or Log(LogLevelFatal, ...). The call returns after OnFatal returns.
+	OnFatal func(c Call)
+
+	// OnPanic, if set, is called instead of the base Logger for a call that would panic, such as Panic,
+	// PanicOnError with a non-nil error, or Log(LogLevelPanic, ...). The call returns after OnPanic returns.
+	OnPanic func(c Call)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Mock is a logger.Logger that records every method call. Unless a method is replaced with Funcs, each call
+// is passed on to a base Logger, which by default discards its output; so Errorf, Sprintf, ForkLogStr, Prefix,
+// GetLogLevel, etc. behave as they would for a real Logger. Loggers returned by the Fork methods are also Mocks,
+// which share the Controls of the Mock they were forked from. Note that the caller reported by a base Logger
+// is a method of Mock, except for the Cd methods.
+type Mock struct {
+	*Controls
+	base logger.Logger
+}
+
+var _ logger.Logger = &Mock{}
+
+// New creates a Mock whose base Logger discards its output, with LogLevelTrace enabled
+func New() *Mock {
+	discard := logger.HandlerFunc(func(r *logger.Record) error {
+		return nil
+	})
+	return NewWithBase(logger.NewWithHandler(discard, "", logger.LogLevelTrace))
+}
+
+// NewWithBase creates a Mock that passes calls on to a base Logger
+func NewWithBase(base logger.Logger) *Mock {
+	m := &Mock{
+		Controls: &Controls{},
+		base:     base,
+	}
+	return m
+}
+
+// Base returns the base Logger of the Mock
+func (m *Mock) Base() logger.Logger {
+	return m.base
+}
+
+// fork creates a Mock that shares the Controls of m, with a new base Logger
+func (m *Mock) fork(base logger.Logger) logger.Logger {
+	return &Mock{Controls: m.Controls, base: base}
+}
+
+// record records a call
+func (m *Mock) record(method string, args []interface{}) Call {
+	c := Call{Method: method, Prefix: m.base.Prefix(), Args: args}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, c)
+	return c
+}
+
+// intercept calls OnFatal or OnPanic if a call at a given level would exit or panic, and the corresponding
+// function is set. It returns true if the call was intercepted.
+func (m *Mock) intercept(c Call, logLevel logger.LogLevel) bool {
+	switch {
+	case logLevel == logger.LogLevelFatal && m.OnFatal != nil:
+		m.OnFatal(c)
+		return true
+	case logLevel == logger.LogLevelPanic && m.OnPanic != nil:
+		m.OnPanic(c)
+		return true
+	}
+	return false
+}
+
+// Calls returns a copy of the recorded calls, in the order in which they were made
+func (ctl *Controls) Calls() []Call {
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+	return append([]Call(nil), ctl.calls...)
+}
+
+// CallsTo returns the recorded calls to a given method, in the order in which they were made
+func (ctl *Controls) CallsTo(method string) []Call {
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+	var result []Call
+	for _, c := range ctl.calls {
+		if c.Method == method {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// Reset discards the recorded calls
+func (ctl *Controls) Reset() {
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+	ctl.calls = nil
+}
@@ -0,0 +1,842 @@
+// Code generated by gen.go; DO NOT EDIT.
+
+package loggermock
+
+import (
+	"github.com/sammck-go/logger"
+	"io"
+)
+
+// Funcs holds optional replacements for the methods of a Mock. If a field is set, the
+// corresponding method calls it after recording the call, rather than calling the base Logger.
+type Funcs struct {
+	CdError          func(arg0 int, arg1 ...interface{}) (r0 error)
+	CdErrorf         func(arg0 int, arg1 string, arg2 ...interface{}) (r0 error)
+	CdLog            func(arg0 int, arg1 logger.LogLevel, arg2 ...interface{})
+	CdLogError       func(arg0 int, arg1 logger.LogLevel, arg2 ...interface{}) (r0 error)
+	CdLogErrorf      func(arg0 int, arg1 logger.LogLevel, arg2 string, arg3 ...interface{}) (r0 error)
+	CdLogNoPrefix    func(arg0 int, arg1 logger.LogLevel, arg2 ...interface{})
+	CdLogStrNoPrefix func(arg0 int, arg1 logger.LogLevel, arg2 string)
+	CdLogf           func(arg0 int, arg1 logger.LogLevel, arg2 string, arg3 ...interface{})
+	CdLogfNoPrefix   func(arg0 int, arg1 logger.LogLevel, arg2 string, arg3 ...interface{})
+	CdLogw           func(arg0 int, arg1 logger.LogLevel, arg2 string, arg3 ...interface{})
+	CdPanic          func(arg0 int, arg1 ...interface{})
+	CdPanicOnError   func(arg0 int, arg1 error)
+	CdPrint          func(arg0 int, arg1 ...interface{})
+	CdPrintf         func(arg0 int, arg1 string, arg2 ...interface{})
+	CdRawOutput      func(arg0 int, arg1 string)
+	CdSprint         func(arg0 int, arg1 ...interface{}) (r0 string)
+	CdSprintf        func(arg0 int, arg1 string, arg2 ...interface{}) (r0 string)
+	DLog             func(arg0 ...interface{})
+	DLogError        func(arg0 ...interface{}) (r0 error)
+	DLogErrorf       func(arg0 string, arg1 ...interface{}) (r0 error)
+	DLogf            func(arg0 string, arg1 ...interface{})
+	DLogw            func(arg0 string, arg1 ...interface{})
+	ELog             func(arg0 ...interface{})
+	ELogError        func(arg0 ...interface{}) (r0 error)
+	ELogErrorf       func(arg0 string, arg1 ...interface{}) (r0 error)
+	ELogf            func(arg0 string, arg1 ...interface{})
+	ELogw            func(arg0 string, arg1 ...interface{})
+	Error            func(arg0 ...interface{}) (r0 error)
+	Errorf           func(arg0 string, arg1 ...interface{}) (r0 error)
+	Fatal            func(arg0 ...interface{})
+	Fatalf           func(arg0 string, arg1 ...interface{})
+	Fields           func() (r0 []logger.Field)
+	ForkLog          func(arg0 ...interface{}) (r0 logger.Logger)
+	ForkLogStr       func(arg0 string) (r0 logger.Logger)
+	ForkLogf         func(arg0 string, arg1 ...interface{}) (r0 logger.Logger)
+	ForkWith         func(arg0 ...interface{}) (r0 logger.Logger)
+	GetLogLevel      func() (r0 logger.LogLevel)
+	ILog             func(arg0 ...interface{})
+	ILogError        func(arg0 ...interface{}) (r0 error)
+	ILogErrorf       func(arg0 string, arg1 ...interface{}) (r0 error)
+	ILogf            func(arg0 string, arg1 ...interface{})
+	ILogw            func(arg0 string, arg1 ...interface{})
+	Log              func(arg0 logger.LogLevel, arg1 ...interface{})
+	LogError         func(arg0 logger.LogLevel, arg1 ...interface{}) (r0 error)
+	LogErrorf        func(arg0 logger.LogLevel, arg1 string, arg2 ...interface{}) (r0 error)
+	LogNoPrefix      func(arg0 logger.LogLevel, arg1 ...interface{})
+	LogStrNoPrefix   func(arg0 logger.LogLevel, arg1 string)
+	Logf             func(arg0 logger.LogLevel, arg1 string, arg2 ...interface{})
+	LogfNoPrefix     func(arg0 logger.LogLevel, arg1 string, arg2 ...interface{})
+	Logw             func(arg0 logger.LogLevel, arg1 string, arg2 ...interface{})
+	Output           func(arg0 int, arg1 string) (r0 error)
+	Panic            func(arg0 ...interface{})
+	PanicOnError     func(arg0 error)
+	Panicf           func(arg0 string, arg1 ...interface{})
+	Prefix           func() (r0 string)
+	PrefixPath       func() (r0 []string)
+	Print            func(arg0 ...interface{})
+	Printf           func(arg0 string, arg1 ...interface{})
+	SetLogLevel      func(arg0 logger.LogLevel)
+	Sprint           func(arg0 ...interface{}) (r0 string)
+	Sprintf          func(arg0 string, arg1 ...interface{}) (r0 string)
+	TLog             func(arg0 ...interface{})
+	TLogError        func(arg0 ...interface{}) (r0 error)
+	TLogErrorf       func(arg0 string, arg1 ...interface{}) (r0 error)
+	TLogf            func(arg0 string, arg1 ...interface{})
+	TLogw            func(arg0 string, arg1 ...interface{})
+	WLog             func(arg0 ...interface{})
+	WLogError        func(arg0 ...interface{}) (r0 error)
+	WLogErrorf       func(arg0 string, arg1 ...interface{}) (r0 error)
+	WLogf            func(arg0 string, arg1 ...interface{})
+	WLogw            func(arg0 string, arg1 ...interface{})
+	Writer           func(arg0 logger.LogLevel) (r0 io.WriteCloser)
+}
+
+// CdError records the call, then calls Funcs.CdError if it is set, or the base Logger's CdError otherwise
+func (m *Mock) CdError(arg0 int, arg1 ...interface{}) (r0 error) {
+	m.record("CdError", []interface{}{arg0, arg1})
+	if fn := m.Funcs.CdError; fn != nil {
+		return fn(arg0, arg1...)
+	}
+	return m.base.CdError(arg0+1, arg1...)
+}
+
+// CdErrorf records the call, then calls Funcs.CdErrorf if it is set, or the base Logger's CdErrorf otherwise
+func (m *Mock) CdErrorf(arg0 int, arg1 string, arg2 ...interface{}) (r0 error) {
+	m.record("CdErrorf", []interface{}{arg0, arg1, arg2})
+	if fn := m.Funcs.CdErrorf; fn != nil {
+		return fn(arg0, arg1, arg2...)
+	}
+	return m.base.CdErrorf(arg0+1, arg1, arg2...)
+}
+
+// CdLog records the call, then calls Funcs.CdLog if it is set, or the base Logger's CdLog otherwise
+func (m *Mock) CdLog(arg0 int, arg1 logger.LogLevel, arg2 ...interface{}) {
+	c := m.record("CdLog", []interface{}{arg0, arg1, arg2})
+	if fn := m.Funcs.CdLog; fn != nil {
+		fn(arg0, arg1, arg2...)
+		return
+	}
+	if m.intercept(c, arg1) {
+		return
+	}
+	m.base.CdLog(arg0+1, arg1, arg2...)
+}
+
+// CdLogError records the call, then calls Funcs.CdLogError if it is set, or the base Logger's CdLogError otherwise
+func (m *Mock) CdLogError(arg0 int, arg1 logger.LogLevel, arg2 ...interface{}) (r0 error) {
+	c := m.record("CdLogError", []interface{}{arg0, arg1, arg2})
+	if fn := m.Funcs.CdLogError; fn != nil {
+		return fn(arg0, arg1, arg2...)
+	}
+	if m.intercept(c, arg1) {
+		return
+	}
+	return m.base.CdLogError(arg0+1, arg1, arg2...)
+}
+
+// CdLogErrorf records the call, then calls Funcs.CdLogErrorf if it is set, or the base Logger's CdLogErrorf otherwise
+func (m *Mock) CdLogErrorf(arg0 int, arg1 logger.LogLevel, arg2 string, arg3 ...interface{}) (r0 error) {
+	c := m.record("CdLogErrorf", []interface{}{arg0, arg1, arg2, arg3})
+	if fn := m.Funcs.CdLogErrorf; fn != nil {
+		return fn(arg0, arg1, arg2, arg3...)
+	}
+	if m.intercept(c, arg1) {
+		return
+	}
+	return m.base.CdLogErrorf(arg0+1, arg1, arg2, arg3...)
+}
+
+// CdLogNoPrefix records the call, then calls Funcs.CdLogNoPrefix if it is set, or the base Logger's CdLogNoPrefix otherwise
+func (m *Mock) CdLogNoPrefix(arg0 int, arg1 logger.LogLevel, arg2 ...interface{}) {
+	c := m.record("CdLogNoPrefix", []interface{}{arg0, arg1, arg2})
+	if fn := m.Funcs.CdLogNoPrefix; fn != nil {
+		fn(arg0, arg1, arg2...)
+		return
+	}
+	if m.intercept(c, arg1) {
+		return
+	}
+	m.base.CdLogNoPrefix(arg0+1, arg1, arg2...)
+}
+
+// CdLogStrNoPrefix records the call, then calls Funcs.CdLogStrNoPrefix if it is set, or the base Logger's CdLogStrNoPrefix otherwise
+func (m *Mock) CdLogStrNoPrefix(arg0 int, arg1 logger.LogLevel, arg2 string) {
+	c := m.record("CdLogStrNoPrefix", []interface{}{arg0, arg1, arg2})
+	if fn := m.Funcs.CdLogStrNoPrefix; fn != nil {
+		fn(arg0, arg1, arg2)
+		return
+	}
+	if m.intercept(c, arg1) {
+		return
+	}
+	m.base.CdLogStrNoPrefix(arg0+1, arg1, arg2)
+}
+
+// CdLogf records the call, then calls Funcs.CdLogf if it is set, or the base Logger's CdLogf otherwise
+func (m *Mock) CdLogf(arg0 int, arg1 logger.LogLevel, arg2 string, arg3 ...interface{}) {
+	c := m.record("CdLogf", []interface{}{arg0, arg1, arg2, arg3})
+	if fn := m.Funcs.CdLogf; fn != nil {
+		fn(arg0, arg1, arg2, arg3...)
+		return
+	}
+	if m.intercept(c, arg1) {
+		return
+	}
+	m.base.CdLogf(arg0+1, arg1, arg2, arg3...)
+}
+
+// CdLogfNoPrefix records the call, then calls Funcs.CdLogfNoPrefix if it is set, or the base Logger's CdLogfNoPrefix otherwise
+func (m *Mock) CdLogfNoPrefix(arg0 int, arg1 logger.LogLevel, arg2 string, arg3 ...interface{}) {
+	c := m.record("CdLogfNoPrefix", []interface{}{arg0, arg1, arg2, arg3})
+	if fn := m.Funcs.CdLogfNoPrefix; fn != nil {
+		fn(arg0, arg1, arg2, arg3...)
+		return
+	}
+	if m.intercept(c, arg1) {
+		return
+	}
+	m.base.CdLogfNoPrefix(arg0+1, arg1, arg2, arg3...)
+}
+
+// CdLogw records the call, then calls Funcs.CdLogw if it is set, or the base Logger's CdLogw otherwise
+func (m *Mock) CdLogw(arg0 int, arg1 logger.LogLevel, arg2 string, arg3 ...interface{}) {
+	c := m.record("CdLogw", []interface{}{arg0, arg1, arg2, arg3})
+	if fn := m.Funcs.CdLogw; fn != nil {
+		fn(arg0, arg1, arg2, arg3...)
+		return
+	}
+	if m.intercept(c, arg1) {
+		return
+	}
+	m.base.CdLogw(arg0+1, arg1, arg2, arg3...)
+}
+
+// CdPanic records the call, then calls Funcs.CdPanic if it is set, or the base Logger's CdPanic otherwise
+func (m *Mock) CdPanic(arg0 int, arg1 ...interface{}) {
+	c := m.record("CdPanic", []interface{}{arg0, arg1})
+	if fn := m.Funcs.CdPanic; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	if m.intercept(c, logger.LogLevelPanic) {
+		return
+	}
+	m.base.CdPanic(arg0+1, arg1...)
+}
+
+// CdPanicOnError records the call, then calls Funcs.CdPanicOnError if it is set, or the base Logger's CdPanicOnError otherwise
+func (m *Mock) CdPanicOnError(arg0 int, arg1 error) {
+	c := m.record("CdPanicOnError", []interface{}{arg0, arg1})
+	if fn := m.Funcs.CdPanicOnError; fn != nil {
+		fn(arg0, arg1)
+		return
+	}
+	if arg1 != nil && m.intercept(c, logger.LogLevelPanic) {
+		return
+	}
+	m.base.CdPanicOnError(arg0+1, arg1)
+}
+
+// CdPrint records the call, then calls Funcs.CdPrint if it is set, or the base Logger's CdPrint otherwise
+func (m *Mock) CdPrint(arg0 int, arg1 ...interface{}) {
+	m.record("CdPrint", []interface{}{arg0, arg1})
+	if fn := m.Funcs.CdPrint; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	m.base.CdPrint(arg0+1, arg1...)
+}
+
+// CdPrintf records the call, then calls Funcs.CdPrintf if it is set, or the base Logger's CdPrintf otherwise
+func (m *Mock) CdPrintf(arg0 int, arg1 string, arg2 ...interface{}) {
+	m.record("CdPrintf", []interface{}{arg0, arg1, arg2})
+	if fn := m.Funcs.CdPrintf; fn != nil {
+		fn(arg0, arg1, arg2...)
+		return
+	}
+	m.base.CdPrintf(arg0+1, arg1, arg2...)
+}
+
+// CdRawOutput records the call, then calls Funcs.CdRawOutput if it is set, or the base Logger's CdRawOutput otherwise
+func (m *Mock) CdRawOutput(arg0 int, arg1 string) {
+	m.record("CdRawOutput", []interface{}{arg0, arg1})
+	if fn := m.Funcs.CdRawOutput; fn != nil {
+		fn(arg0, arg1)
+		return
+	}
+	m.base.CdRawOutput(arg0+1, arg1)
+}
+
+// CdSprint records the call, then calls Funcs.CdSprint if it is set, or the base Logger's CdSprint otherwise
+func (m *Mock) CdSprint(arg0 int, arg1 ...interface{}) (r0 string) {
+	m.record("CdSprint", []interface{}{arg0, arg1})
+	if fn := m.Funcs.CdSprint; fn != nil {
+		return fn(arg0, arg1...)
+	}
+	return m.base.CdSprint(arg0+1, arg1...)
+}
+
+// CdSprintf records the call, then calls Funcs.CdSprintf if it is set, or the base Logger's CdSprintf otherwise
+func (m *Mock) CdSprintf(arg0 int, arg1 string, arg2 ...interface{}) (r0 string) {
+	m.record("CdSprintf", []interface{}{arg0, arg1, arg2})
+	if fn := m.Funcs.CdSprintf; fn != nil {
+		return fn(arg0, arg1, arg2...)
+	}
+	return m.base.CdSprintf(arg0+1, arg1, arg2...)
+}
+
+// DLog records the call, then calls Funcs.DLog if it is set, or the base Logger's DLog otherwise
+func (m *Mock) DLog(arg0 ...interface{}) {
+	m.record("DLog", []interface{}{arg0})
+	if fn := m.Funcs.DLog; fn != nil {
+		fn(arg0...)
+		return
+	}
+	m.base.DLog(arg0...)
+}
+
+// DLogError records the call, then calls Funcs.DLogError if it is set, or the base Logger's DLogError otherwise
+func (m *Mock) DLogError(arg0 ...interface{}) (r0 error) {
+	m.record("DLogError", []interface{}{arg0})
+	if fn := m.Funcs.DLogError; fn != nil {
+		return fn(arg0...)
+	}
+	return m.base.DLogError(arg0...)
+}
+
+// DLogErrorf records the call, then calls Funcs.DLogErrorf if it is set, or the base Logger's DLogErrorf otherwise
+func (m *Mock) DLogErrorf(arg0 string, arg1 ...interface{}) (r0 error) {
+	m.record("DLogErrorf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.DLogErrorf; fn != nil {
+		return fn(arg0, arg1...)
+	}
+	return m.base.DLogErrorf(arg0, arg1...)
+}
+
+// DLogf records the call, then calls Funcs.DLogf if it is set, or the base Logger's DLogf otherwise
+func (m *Mock) DLogf(arg0 string, arg1 ...interface{}) {
+	m.record("DLogf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.DLogf; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	m.base.DLogf(arg0, arg1...)
+}
+
+// DLogw records the call, then calls Funcs.DLogw if it is set, or the base Logger's DLogw otherwise
+func (m *Mock) DLogw(arg0 string, arg1 ...interface{}) {
+	m.record("DLogw", []interface{}{arg0, arg1})
+	if fn := m.Funcs.DLogw; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	m.base.DLogw(arg0, arg1...)
+}
+
+// ELog records the call, then calls Funcs.ELog if it is set, or the base Logger's ELog otherwise
+func (m *Mock) ELog(arg0 ...interface{}) {
+	m.record("ELog", []interface{}{arg0})
+	if fn := m.Funcs.ELog; fn != nil {
+		fn(arg0...)
+		return
+	}
+	m.base.ELog(arg0...)
+}
+
+// ELogError records the call, then calls Funcs.ELogError if it is set, or the base Logger's ELogError otherwise
+func (m *Mock) ELogError(arg0 ...interface{}) (r0 error) {
+	m.record("ELogError", []interface{}{arg0})
+	if fn := m.Funcs.ELogError; fn != nil {
+		return fn(arg0...)
+	}
+	return m.base.ELogError(arg0...)
+}
+
+// ELogErrorf records the call, then calls Funcs.ELogErrorf if it is set, or the base Logger's ELogErrorf otherwise
+func (m *Mock) ELogErrorf(arg0 string, arg1 ...interface{}) (r0 error) {
+	m.record("ELogErrorf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.ELogErrorf; fn != nil {
+		return fn(arg0, arg1...)
+	}
+	return m.base.ELogErrorf(arg0, arg1...)
+}
+
+// ELogf records the call, then calls Funcs.ELogf if it is set, or the base Logger's ELogf otherwise
+func (m *Mock) ELogf(arg0 string, arg1 ...interface{}) {
+	m.record("ELogf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.ELogf; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	m.base.ELogf(arg0, arg1...)
+}
+
+// ELogw records the call, then calls Funcs.ELogw if it is set, or the base Logger's ELogw otherwise
+func (m *Mock) ELogw(arg0 string, arg1 ...interface{}) {
+	m.record("ELogw", []interface{}{arg0, arg1})
+	if fn := m.Funcs.ELogw; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	m.base.ELogw(arg0, arg1...)
+}
+
+// Error records the call, then calls Funcs.Error if it is set, or the base Logger's Error otherwise
+func (m *Mock) Error(arg0 ...interface{}) (r0 error) {
+	m.record("Error", []interface{}{arg0})
+	if fn := m.Funcs.Error; fn != nil {
+		return fn(arg0...)
+	}
+	return m.base.Error(arg0...)
+}
+
+// Errorf records the call, then calls Funcs.Errorf if it is set, or the base Logger's Errorf otherwise
+func (m *Mock) Errorf(arg0 string, arg1 ...interface{}) (r0 error) {
+	m.record("Errorf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.Errorf; fn != nil {
+		return fn(arg0, arg1...)
+	}
+	return m.base.Errorf(arg0, arg1...)
+}
+
+// Fatal records the call, then calls Funcs.Fatal if it is set, or the base Logger's Fatal otherwise
+func (m *Mock) Fatal(arg0 ...interface{}) {
+	c := m.record("Fatal", []interface{}{arg0})
+	if fn := m.Funcs.Fatal; fn != nil {
+		fn(arg0...)
+		return
+	}
+	if m.intercept(c, logger.LogLevelFatal) {
+		return
+	}
+	m.base.Fatal(arg0...)
+}
+
+// Fatalf records the call, then calls Funcs.Fatalf if it is set, or the base Logger's Fatalf otherwise
+func (m *Mock) Fatalf(arg0 string, arg1 ...interface{}) {
+	c := m.record("Fatalf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.Fatalf; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	if m.intercept(c, logger.LogLevelFatal) {
+		return
+	}
+	m.base.Fatalf(arg0, arg1...)
+}
+
+// Fields records the call, then calls Funcs.Fields if it is set, or the base Logger's Fields otherwise
+func (m *Mock) Fields() (r0 []logger.Field) {
+	m.record("Fields", []interface{}{})
+	if fn := m.Funcs.Fields; fn != nil {
+		return fn()
+	}
+	return m.base.Fields()
+}
+
+// ForkLog records the call, then calls Funcs.ForkLog if it is set, or the base Logger's ForkLog otherwise
+func (m *Mock) ForkLog(arg0 ...interface{}) (r0 logger.Logger) {
+	m.record("ForkLog", []interface{}{arg0})
+	if fn := m.Funcs.ForkLog; fn != nil {
+		return fn(arg0...)
+	}
+	return m.fork(m.base.ForkLog(arg0...))
+}
+
+// ForkLogStr records the call, then calls Funcs.ForkLogStr if it is set, or the base Logger's ForkLogStr otherwise
+func (m *Mock) ForkLogStr(arg0 string) (r0 logger.Logger) {
+	m.record("ForkLogStr", []interface{}{arg0})
+	if fn := m.Funcs.ForkLogStr; fn != nil {
+		return fn(arg0)
+	}
+	return m.fork(m.base.ForkLogStr(arg0))
+}
+
+// ForkLogf records the call, then calls Funcs.ForkLogf if it is set, or the base Logger's ForkLogf otherwise
+func (m *Mock) ForkLogf(arg0 string, arg1 ...interface{}) (r0 logger.Logger) {
+	m.record("ForkLogf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.ForkLogf; fn != nil {
+		return fn(arg0, arg1...)
+	}
+	return m.fork(m.base.ForkLogf(arg0, arg1...))
+}
+
+// ForkWith records the call, then calls Funcs.ForkWith if it is set, or the base Logger's ForkWith otherwise
+func (m *Mock) ForkWith(arg0 ...interface{}) (r0 logger.Logger) {
+	m.record("ForkWith", []interface{}{arg0})
+	if fn := m.Funcs.ForkWith; fn != nil {
+		return fn(arg0...)
+	}
+	return m.fork(m.base.ForkWith(arg0...))
+}
+
+// GetLogLevel records the call, then calls Funcs.GetLogLevel if it is set, or the base Logger's GetLogLevel otherwise
+func (m *Mock) GetLogLevel() (r0 logger.LogLevel) {
+	m.record("GetLogLevel", []interface{}{})
+	if fn := m.Funcs.GetLogLevel; fn != nil {
+		return fn()
+	}
+	return m.base.GetLogLevel()
+}
+
+// ILog records the call, then calls Funcs.ILog if it is set, or the base Logger's ILog otherwise
+func (m *Mock) ILog(arg0 ...interface{}) {
+	m.record("ILog", []interface{}{arg0})
+	if fn := m.Funcs.ILog; fn != nil {
+		fn(arg0...)
+		return
+	}
+	m.base.ILog(arg0...)
+}
+
+// ILogError records the call, then calls Funcs.ILogError if it is set, or the base Logger's ILogError otherwise
+func (m *Mock) ILogError(arg0 ...interface{}) (r0 error) {
+	m.record("ILogError", []interface{}{arg0})
+	if fn := m.Funcs.ILogError; fn != nil {
+		return fn(arg0...)
+	}
+	return m.base.ILogError(arg0...)
+}
+
+// ILogErrorf records the call, then calls Funcs.ILogErrorf if it is set, or the base Logger's ILogErrorf otherwise
+func (m *Mock) ILogErrorf(arg0 string, arg1 ...interface{}) (r0 error) {
+	m.record("ILogErrorf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.ILogErrorf; fn != nil {
+		return fn(arg0, arg1...)
+	}
+	return m.base.ILogErrorf(arg0, arg1...)
+}
+
+// ILogf records the call, then calls Funcs.ILogf if it is set, or the base Logger's ILogf otherwise
+func (m *Mock) ILogf(arg0 string, arg1 ...interface{}) {
+	m.record("ILogf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.ILogf; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	m.base.ILogf(arg0, arg1...)
+}
+
+// ILogw records the call, then calls Funcs.ILogw if it is set, or the base Logger's ILogw otherwise
+func (m *Mock) ILogw(arg0 string, arg1 ...interface{}) {
+	m.record("ILogw", []interface{}{arg0, arg1})
+	if fn := m.Funcs.ILogw; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	m.base.ILogw(arg0, arg1...)
+}
+
+// Log records the call, then calls Funcs.Log if it is set, or the base Logger's Log otherwise
+func (m *Mock) Log(arg0 logger.LogLevel, arg1 ...interface{}) {
+	c := m.record("Log", []interface{}{arg0, arg1})
+	if fn := m.Funcs.Log; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	if m.intercept(c, arg0) {
+		return
+	}
+	m.base.Log(arg0, arg1...)
+}
+
+// LogError records the call, then calls Funcs.LogError if it is set, or the base Logger's LogError otherwise
+func (m *Mock) LogError(arg0 logger.LogLevel, arg1 ...interface{}) (r0 error) {
+	c := m.record("LogError", []interface{}{arg0, arg1})
+	if fn := m.Funcs.LogError; fn != nil {
+		return fn(arg0, arg1...)
+	}
+	if m.intercept(c, arg0) {
+		return
+	}
+	return m.base.LogError(arg0, arg1...)
+}
+
+// LogErrorf records the call, then calls Funcs.LogErrorf if it is set, or the base Logger's LogErrorf otherwise
+func (m *Mock) LogErrorf(arg0 logger.LogLevel, arg1 string, arg2 ...interface{}) (r0 error) {
+	c := m.record("LogErrorf", []interface{}{arg0, arg1, arg2})
+	if fn := m.Funcs.LogErrorf; fn != nil {
+		return fn(arg0, arg1, arg2...)
+	}
+	if m.intercept(c, arg0) {
+		return
+	}
+	return m.base.LogErrorf(arg0, arg1, arg2...)
+}
+
+// LogNoPrefix records the call, then calls Funcs.LogNoPrefix if it is set, or the base Logger's LogNoPrefix otherwise
+func (m *Mock) LogNoPrefix(arg0 logger.LogLevel, arg1 ...interface{}) {
+	c := m.record("LogNoPrefix", []interface{}{arg0, arg1})
+	if fn := m.Funcs.LogNoPrefix; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	if m.intercept(c, arg0) {
+		return
+	}
+	m.base.LogNoPrefix(arg0, arg1...)
+}
+
+// LogStrNoPrefix records the call, then calls Funcs.LogStrNoPrefix if it is set, or the base Logger's LogStrNoPrefix otherwise
+func (m *Mock) LogStrNoPrefix(arg0 logger.LogLevel, arg1 string) {
+	c := m.record("LogStrNoPrefix", []interface{}{arg0, arg1})
+	if fn := m.Funcs.LogStrNoPrefix; fn != nil {
+		fn(arg0, arg1)
+		return
+	}
+	if m.intercept(c, arg0) {
+		return
+	}
+	m.base.LogStrNoPrefix(arg0, arg1)
+}
+
+// Logf records the call, then calls Funcs.Logf if it is set, or the base Logger's Logf otherwise
+func (m *Mock) Logf(arg0 logger.LogLevel, arg1 string, arg2 ...interface{}) {
+	c := m.record("Logf", []interface{}{arg0, arg1, arg2})
+	if fn := m.Funcs.Logf; fn != nil {
+		fn(arg0, arg1, arg2...)
+		return
+	}
+	if m.intercept(c, arg0) {
+		return
+	}
+	m.base.Logf(arg0, arg1, arg2...)
+}
+
+// LogfNoPrefix records the call, then calls Funcs.LogfNoPrefix if it is set, or the base Logger's LogfNoPrefix otherwise
+func (m *Mock) LogfNoPrefix(arg0 logger.LogLevel, arg1 string, arg2 ...interface{}) {
+	c := m.record("LogfNoPrefix", []interface{}{arg0, arg1, arg2})
+	if fn := m.Funcs.LogfNoPrefix; fn != nil {
+		fn(arg0, arg1, arg2...)
+		return
+	}
+	if m.intercept(c, arg0) {
+		return
+	}
+	m.base.LogfNoPrefix(arg0, arg1, arg2...)
+}
+
+// Logw records the call, then calls Funcs.Logw if it is set, or the base Logger's Logw otherwise
+func (m *Mock) Logw(arg0 logger.LogLevel, arg1 string, arg2 ...interface{}) {
+	c := m.record("Logw", []interface{}{arg0, arg1, arg2})
+	if fn := m.Funcs.Logw; fn != nil {
+		fn(arg0, arg1, arg2...)
+		return
+	}
+	if m.intercept(c, arg0) {
+		return
+	}
+	m.base.Logw(arg0, arg1, arg2...)
+}
+
+// Output records the call, then calls Funcs.Output if it is set, or the base Logger's Output otherwise
+func (m *Mock) Output(arg0 int, arg1 string) (r0 error) {
+	m.record("Output", []interface{}{arg0, arg1})
+	if fn := m.Funcs.Output; fn != nil {
+		return fn(arg0, arg1)
+	}
+	return m.base.Output(arg0, arg1)
+}
+
+// Panic records the call, then calls Funcs.Panic if it is set, or the base Logger's Panic otherwise
+func (m *Mock) Panic(arg0 ...interface{}) {
+	c := m.record("Panic", []interface{}{arg0})
+	if fn := m.Funcs.Panic; fn != nil {
+		fn(arg0...)
+		return
+	}
+	if m.intercept(c, logger.LogLevelPanic) {
+		return
+	}
+	m.base.Panic(arg0...)
+}
+
+// PanicOnError records the call, then calls Funcs.PanicOnError if it is set, or the base Logger's PanicOnError otherwise
+func (m *Mock) PanicOnError(arg0 error) {
+	c := m.record("PanicOnError", []interface{}{arg0})
+	if fn := m.Funcs.PanicOnError; fn != nil {
+		fn(arg0)
+		return
+	}
+	if arg0 != nil && m.intercept(c, logger.LogLevelPanic) {
+		return
+	}
+	m.base.PanicOnError(arg0)
+}
+
+// Panicf records the call, then calls Funcs.Panicf if it is set, or the base Logger's Panicf otherwise
+func (m *Mock) Panicf(arg0 string, arg1 ...interface{}) {
+	c := m.record("Panicf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.Panicf; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	if m.intercept(c, logger.LogLevelPanic) {
+		return
+	}
+	m.base.Panicf(arg0, arg1...)
+}
+
+// Prefix records the call, then calls Funcs.Prefix if it is set, or the base Logger's Prefix otherwise
+func (m *Mock) Prefix() (r0 string) {
+	m.record("Prefix", []interface{}{})
+	if fn := m.Funcs.Prefix; fn != nil {
+		return fn()
+	}
+	return m.base.Prefix()
+}
+
+// PrefixPath records the call, then calls Funcs.PrefixPath if it is set, or the base Logger's PrefixPath otherwise
+func (m *Mock) PrefixPath() (r0 []string) {
+	m.record("PrefixPath", []interface{}{})
+	if fn := m.Funcs.PrefixPath; fn != nil {
+		return fn()
+	}
+	return m.base.PrefixPath()
+}
+
+// Print records the call, then calls Funcs.Print if it is set, or the base Logger's Print otherwise
+func (m *Mock) Print(arg0 ...interface{}) {
+	m.record("Print", []interface{}{arg0})
+	if fn := m.Funcs.Print; fn != nil {
+		fn(arg0...)
+		return
+	}
+	m.base.Print(arg0...)
+}
+
+// Printf records the call, then calls Funcs.Printf if it is set, or the base Logger's Printf otherwise
+func (m *Mock) Printf(arg0 string, arg1 ...interface{}) {
+	m.record("Printf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.Printf; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	m.base.Printf(arg0, arg1...)
+}
+
+// SetLogLevel records the call, then calls Funcs.SetLogLevel if it is set, or the base Logger's SetLogLevel otherwise
+func (m *Mock) SetLogLevel(arg0 logger.LogLevel) {
+	m.record("SetLogLevel", []interface{}{arg0})
+	if fn := m.Funcs.SetLogLevel; fn != nil {
+		fn(arg0)
+		return
+	}
+	m.base.SetLogLevel(arg0)
+}
+
+// Sprint records the call, then calls Funcs.Sprint if it is set, or the base Logger's Sprint otherwise
+func (m *Mock) Sprint(arg0 ...interface{}) (r0 string) {
+	m.record("Sprint", []interface{}{arg0})
+	if fn := m.Funcs.Sprint; fn != nil {
+		return fn(arg0...)
+	}
+	return m.base.Sprint(arg0...)
+}
+
+// Sprintf records the call, then calls Funcs.Sprintf if it is set, or the base Logger's Sprintf otherwise
+func (m *Mock) Sprintf(arg0 string, arg1 ...interface{}) (r0 string) {
+	m.record("Sprintf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.Sprintf; fn != nil {
+		return fn(arg0, arg1...)
+	}
+	return m.base.Sprintf(arg0, arg1...)
+}
+
+// TLog records the call, then calls Funcs.TLog if it is set, or the base Logger's TLog otherwise
+func (m *Mock) TLog(arg0 ...interface{}) {
+	m.record("TLog", []interface{}{arg0})
+	if fn := m.Funcs.TLog; fn != nil {
+		fn(arg0...)
+		return
+	}
+	m.base.TLog(arg0...)
+}
+
+// TLogError records the call, then calls Funcs.TLogError if it is set, or the base Logger's TLogError otherwise
+func (m *Mock) TLogError(arg0 ...interface{}) (r0 error) {
+	m.record("TLogError", []interface{}{arg0})
+	if fn := m.Funcs.TLogError; fn != nil {
+		return fn(arg0...)
+	}
+	return m.base.TLogError(arg0...)
+}
+
+// TLogErrorf records the call, then calls Funcs.TLogErrorf if it is set, or the base Logger's TLogErrorf otherwise
+func (m *Mock) TLogErrorf(arg0 string, arg1 ...interface{}) (r0 error) {
+	m.record("TLogErrorf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.TLogErrorf; fn != nil {
+		return fn(arg0, arg1...)
+	}
+	return m.base.TLogErrorf(arg0, arg1...)
+}
+
+// TLogf records the call, then calls Funcs.TLogf if it is set, or the base Logger's TLogf otherwise
+func (m *Mock) TLogf(arg0 string, arg1 ...interface{}) {
+	m.record("TLogf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.TLogf; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	m.base.TLogf(arg0, arg1...)
+}
+
+// TLogw records the call, then calls Funcs.TLogw if it is set, or the base Logger's TLogw otherwise
+func (m *Mock) TLogw(arg0 string, arg1 ...interface{}) {
+	m.record("TLogw", []interface{}{arg0, arg1})
+	if fn := m.Funcs.TLogw; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	m.base.TLogw(arg0, arg1...)
+}
+
+// WLog records the call, then calls Funcs.WLog if it is set, or the base Logger's WLog otherwise
+func (m *Mock) WLog(arg0 ...interface{}) {
+	m.record("WLog", []interface{}{arg0})
+	if fn := m.Funcs.WLog; fn != nil {
+		fn(arg0...)
+		return
+	}
+	m.base.WLog(arg0...)
+}
+
+// WLogError records the call, then calls Funcs.WLogError if it is set, or the base Logger's WLogError otherwise
+func (m *Mock) WLogError(arg0 ...interface{}) (r0 error) {
+	m.record("WLogError", []interface{}{arg0})
+	if fn := m.Funcs.WLogError; fn != nil {
+		return fn(arg0...)
+	}
+	return m.base.WLogError(arg0...)
+}
+
+// WLogErrorf records the call, then calls Funcs.WLogErrorf if it is set, or the base Logger's WLogErrorf otherwise
+func (m *Mock) WLogErrorf(arg0 string, arg1 ...interface{}) (r0 error) {
+	m.record("WLogErrorf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.WLogErrorf; fn != nil {
+		return fn(arg0, arg1...)
+	}
+	return m.base.WLogErrorf(arg0, arg1...)
+}
+
+// WLogf records the call, then calls Funcs.WLogf if it is set, or the base Logger's WLogf otherwise
+func (m *Mock) WLogf(arg0 string, arg1 ...interface{}) {
+	m.record("WLogf", []interface{}{arg0, arg1})
+	if fn := m.Funcs.WLogf; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	m.base.WLogf(arg0, arg1...)
+}
+
+// WLogw records the call, then calls Funcs.WLogw if it is set, or the base Logger's WLogw otherwise
+func (m *Mock) WLogw(arg0 string, arg1 ...interface{}) {
+	m.record("WLogw", []interface{}{arg0, arg1})
+	if fn := m.Funcs.WLogw; fn != nil {
+		fn(arg0, arg1...)
+		return
+	}
+	m.base.WLogw(arg0, arg1...)
+}
+
+// Writer records the call, then calls Funcs.Writer if it is set, or the base Logger's Writer otherwise
+func (m *Mock) Writer(arg0 logger.LogLevel) (r0 io.WriteCloser) {
+	m.record("Writer", []interface{}{arg0})
+	if fn := m.Funcs.Writer; fn != nil {
+		return fn(arg0)
+	}
+	return m.base.Writer(arg0)
+}
@@ -0,0 +1,155 @@
+package loggermock
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sammck-go/logger"
+	"github.com/sammck-go/logger/loggermock/internal/mockgen"
+	"github.com/sammck-go/logger/loggertest"
+)
+
+// TestGeneratedCodeIsCurrent fails if the Logger interface has changed without mock_gen.go being regenerated
+func TestGeneratedCodeIsCurrent(t *testing.T) {
+	expected, err := mockgen.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := os.ReadFile("mock_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("mock_gen.go is out of date with the logger.Logger interface; run go generate")
+	}
+
+	loggerType := reflect.TypeOf((*logger.Logger)(nil)).Elem()
+	funcsType := reflect.TypeOf(Funcs{})
+	if funcsType.NumField() != loggerType.NumMethod() {
+		t.Errorf("Expected %d Funcs fields; got %d", loggerType.NumMethod(), funcsType.NumField())
+	}
+	for i := 0; i < loggerType.NumMethod(); i++ {
+		method := loggerType.Method(i)
+		field, ok := funcsType.FieldByName(method.Name)
+		if !ok || field.Type != method.Type {
+			t.Errorf("Funcs.%s does not match logger.Logger.%s", method.Name, method.Name)
+		}
+	}
+}
+
+func TestRecordsCalls(t *testing.T) {
+	m := New()
+	child := m.ForkLogf("TestObj %d", 2)
+	child.ILogf("hello %s", "world")
+	err := child.Errorf("failed %d", 3)
+	m.SetLogLevel(logger.LogLevelWarning)
+
+	if err == nil || err.Error() != "TestObj 2: failed 3" {
+		t.Errorf("Expected error from base Logger; got %v", err)
+	}
+	if m.GetLogLevel() != logger.LogLevelWarning {
+		t.Errorf("Expected SetLogLevel to be passed to the base Logger")
+	}
+
+	expected := []Call{
+		{Method: "ForkLogf", Prefix: "", Args: []interface{}{"TestObj %d", []interface{}{2}}},
+		{Method: "ILogf", Prefix: "TestObj 2", Args: []interface{}{"hello %s", []interface{}{"world"}}},
+		{Method: "Errorf", Prefix: "TestObj 2", Args: []interface{}{"failed %d", []interface{}{3}}},
+		{Method: "SetLogLevel", Prefix: "", Args: []interface{}{logger.LogLevelWarning}},
+		{Method: "GetLogLevel", Prefix: "", Args: []interface{}{}},
+	}
+	calls := m.Calls()
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected calls %v; got %v", expected, calls)
+	}
+	if n := len(m.CallsTo("ILogf")); n != 1 {
+		t.Errorf("Expected 1 call to ILogf; got %d", n)
+	}
+	m.Reset()
+	if n := len(m.Calls()); n != 0 {
+		t.Errorf("Expected no calls after Reset; got %d", n)
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	m := New()
+	sentinel := errors.New("sentinel")
+	var logged []string
+	m.Funcs.ELogErrorf = func(f string, args ...interface{}) error {
+		return sentinel
+	}
+	m.Funcs.WLog = func(args ...interface{}) {
+		logged = append(logged, fmt.Sprint(args...))
+	}
+
+	if err := m.ForkLogStr("child").ELogErrorf("x"); err != sentinel {
+		t.Errorf("Expected replacement ELogErrorf to be called on a fork; got %v", err)
+	}
+	m.WLog("warned")
+	if len(logged) != 1 || logged[0] != "warned" {
+		t.Errorf("Expected replacement WLog to be called; got %v", logged)
+	}
+}
+
+func TestFatalAndPanic(t *testing.T) {
+	m := New()
+	var intercepted []string
+	m.OnFatal = func(c Call) {
+		intercepted = append(intercepted, "fatal "+c.Method)
+	}
+	m.OnPanic = func(c Call) {
+		intercepted = append(intercepted, "panic "+c.Method)
+	}
+
+	m.Fatal("exit")
+	m.ForkLogStr("child").Logf(logger.LogLevelFatal, "exit %d", 1)
+	m.Panicf("boom")
+	m.PanicOnError(nil)
+	m.PanicOnError(errors.New("boom"))
+	m.CdLog(1, logger.LogLevelPanic, "boom")
+	m.ELog("not intercepted")
+	m.SetLogLevel(logger.LogLevelFatal)
+
+	expected := []string{"fatal Fatal", "fatal Logf", "panic Panicf", "panic PanicOnError", "panic CdLog"}
+	if !reflect.DeepEqual(intercepted, expected) {
+		t.Errorf("Expected %v; got %v", expected, intercepted)
+	}
+
+	// Without OnPanic, the base Logger panics
+	m.OnPanic = nil
+	func() {
+		defer func() {
+			r := recover()
+			if r == nil || !strings.Contains(fmt.Sprint(r), "boom") {
+				t.Errorf("Expected Panic to panic with base Logger; got %v", r)
+			}
+		}()
+		m.Panic("boom")
+	}()
+}
+
+func TestBase(t *testing.T) {
+	rec := loggertest.NewRecorder()
+	m := NewWithBase(rec.NewLogger("base", logger.LogLevelInfo))
+
+	m.ForkWith("k", 1).ILogw("hello")
+	m.CdLogf(1, logger.LogLevelWarning, "attributed to the caller")
+	m.DLog("filtered by the base Logger")
+
+	rec.AssertLogged(t, logger.LogLevelInfo, "base", "^hello k=1$")
+	e := rec.AssertLogged(t, logger.LogLevelWarning, "base", "attributed")
+	if !strings.HasSuffix(e.File, "mock_test.go") {
+		t.Errorf("Expected Cd method to be attributed to mock_test.go; got %s", e.File)
+	}
+	if n := len(rec.Entries()); n != 2 {
+		t.Errorf("Expected 2 entries; got %d", n)
+	}
+	if n := len(m.Calls()); n != 4 {
+		t.Errorf("Expected 4 recorded calls; got %d", n)
+	}
+}
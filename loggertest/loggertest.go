@@ -0,0 +1,136 @@
+/*
+Package loggertest provides a logger.Logger for use in tests, which writes to the test's log through
+testing.TB. Output interleaves with the test's own output, is only shown for failing tests (or with
+go test -v), and is attributed to the source location of the logging call. A Recorder captures log
+events in memory, so that tests can make assertions about what was logged.
+*/
+package loggertest
+
+import (
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sammck-go/logger"
+)
+
+// Option is a function that configures a test Logger
+type Option func(*options)
+
+// options holds the configuration of a test Logger
+type options struct {
+	prefix    string
+	logLevel  logger.LogLevel
+	failLevel logger.LogLevel
+	formatter logger.Formatter
+}
+
+// WithPrefix sets the prefix of the test Logger. The default is no prefix.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithLevel sets the minimum level of records that are written to the test log. The default is
+// logger.LogLevelTrace.
+func WithLevel(logLevel logger.LogLevel) Option {
+	return func(o *options) {
+		o.logLevel = logLevel
+	}
+}
+
+// FailOnLevel marks the test as failed (without stopping it) when a record at the given level or a more
+// severe level is logged
+func FailOnLevel(logLevel logger.LogLevel) Option {
+	return func(o *options) {
+		o.failLevel = logLevel
+	}
+}
+
+// FailOnError marks the test as failed (without stopping it) when a record at logger.LogLevelError or a
+// more severe level is logged, e.g., by ELog or Panic. It is the same as FailOnLevel(logger.LogLevelError).
+func FailOnError() Option {
+	return FailOnLevel(logger.LogLevelError)
+}
+
+// WithFormatter sets the Formatter used to render records. The default renders the caller's short file
+// name and line number, a bracketed level tag, and the record's text.
+func WithFormatter(formatter logger.Formatter) Option {
+	return func(o *options) {
+		o.formatter = formatter
+	}
+}
+
+// handler is a logger.Handler that writes records to a test log
+type handler struct {
+	t         testing.TB
+	failLevel logger.LogLevel
+	formatter logger.Formatter
+	// done is set when the test has completed, after which records are discarded, since testing.TB
+	// must not be used after a test completes
+	done atomic.Bool
+}
+
+// outputer is implemented by testing.TB in Go 1.25 and later
+type outputer interface {
+	Output() io.Writer
+}
+
+// New creates a Logger that writes to the log of a test or benchmark. Records are rendered with the source
+// location of the logging call. If the testing.TB supports Output (Go 1.25 and later), that location
+// replaces the location that testing would otherwise add; on earlier releases, each line also begins with
+// the location within this package that called t.Log.
+//
+// Records at logger.LogLevelFatal call t.FailNow rather than exiting the process, so Fatal must only be
+// called from the goroutine running the test. Records logged after the test completes are discarded.
+func New(t testing.TB, opts ...Option) logger.Logger {
+	t.Helper()
+	o := &options{
+		logLevel:  logger.LogLevelTrace,
+		failLevel: logger.LogLevelUnknown,
+		formatter: logger.NewTextFormatter(
+			logger.FormatOptions{Caller: logger.CallerShort},
+			logger.LevelTag{Style: logger.LevelTagBracketed},
+		),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	h := &handler{
+		t:         t,
+		failLevel: o.failLevel,
+		formatter: o.formatter,
+	}
+	t.Cleanup(func() {
+		h.done.Store(true)
+	})
+	return logger.NewWithHandler(h, o.prefix, o.logLevel)
+}
+
+// Handle writes a Record to the test log, and fails the test if required
+func (h *handler) Handle(r *logger.Record) error {
+	if h.done.Load() {
+		return nil
+	}
+	h.t.Helper()
+	b := h.formatter.Format(nil, r)
+	if o, ok := h.t.(outputer); ok {
+		_, err := o.Output().Write(b)
+		if err != nil {
+			return err
+		}
+	} else {
+		h.t.Log(strings.TrimSuffix(string(b), "\n"))
+	}
+	if r.Level != logger.LogLevelUnknown {
+		if r.Level == logger.LogLevelFatal {
+			h.t.FailNow()
+		}
+		if r.Level <= h.failLevel {
+			h.t.Fail()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package loggertest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sammck-go/logger"
+)
+
+// fakeTB is a testing.TB that captures output and failures
+type fakeTB struct {
+	testing.TB
+	buf       bytes.Buffer
+	failed    bool
+	failedNow bool
+}
+
+func (f *fakeTB) Output() io.Writer {
+	return &f.buf
+}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	fmt.Fprintf(&f.buf, format, args...)
+	f.failed = true
+}
+
+func (f *fakeTB) Fail() {
+	f.failed = true
+}
+
+func (f *fakeTB) FailNow() {
+	f.failed = true
+	f.failedNow = true
+	runtime.Goexit()
+}
+
+// runFake runs fn with a new fakeTB on a separate goroutine, so that FailNow can exit it
+func runFake(t *testing.T, fn func(tb *fakeTB)) *fakeTB {
+	tb := &fakeTB{TB: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(tb)
+	}()
+	<-done
+	return tb
+}
+
+func checkLines(t *testing.T, output string, expectedLines []string) {
+	t.Helper()
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != len(expectedLines) {
+		t.Fatalf("Expected %d lines; got %q", len(expectedLines), output)
+	}
+	for i, expected := range expectedLines {
+		if lines[i] != expected {
+			t.Errorf("Expected [%s]; got [%s]", expected, lines[i])
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	var line int
+	tb := runFake(t, func(tb *fakeTB) {
+		lg := New(tb, WithPrefix("svc"), WithLevel(logger.LogLevelDebug))
+		_, _, line, _ = runtime.Caller(0)
+		lg.ILogf("hello %s", "world")
+		lg.ForkWith("k", 1).DLog("debug")
+		lg.TLog("filtered")
+		lg.ELog("not fatal to the test")
+	})
+	checkLines(t, tb.buf.String(), []string{
+		fmt.Sprintf("loggertest_test.go:%d: [INFO] svc: hello world", line+1),
+		fmt.Sprintf("loggertest_test.go:%d: [DEBUG] svc: debug k=1", line+2),
+		fmt.Sprintf("loggertest_test.go:%d: [ERROR] svc: not fatal to the test", line+4),
+	})
+	if tb.failed {
+		t.Errorf("Expected test to pass without FailOnError")
+	}
+}
+
+func TestFailOnError(t *testing.T) {
+	tb := runFake(t, func(tb *fakeTB) {
+		lg := New(tb, FailOnError())
+		lg.WLog("warning")
+	})
+	if tb.failed {
+		t.Errorf("Expected warning not to fail the test")
+	}
+
+	tb = runFake(t, func(tb *fakeTB) {
+		lg := New(tb, FailOnError())
+		lg.ELog("error")
+	})
+	if !tb.failed || tb.failedNow {
+		t.Errorf("Expected error to fail the test without stopping it")
+	}
+
+	tb = runFake(t, func(tb *fakeTB) {
+		lg := New(tb, FailOnError())
+		defer func() { recover() }()
+		lg.Panic("boom")
+	})
+	if !tb.failed || !strings.HasSuffix(tb.buf.String(), "[PANIC] boom\n") {
+		t.Errorf("Expected panic to be logged and fail the test; got %q", tb.buf.String())
+	}
+}
+
+func TestFatal(t *testing.T) {
+	reached := false
+	tb := runFake(t, func(tb *fakeTB) {
+		lg := New(tb)
+		lg.Fatal("fatal")
+		reached = true
+	})
+	if !tb.failedNow || reached {
+		t.Errorf("Expected Fatal to stop the test with FailNow")
+	}
+	if !strings.HasSuffix(tb.buf.String(), "[FATAL] fatal\n") {
+		t.Errorf("Expected Fatal to be logged; got %q", tb.buf.String())
+	}
+}
+
+func TestRealT(t *testing.T) {
+	lg := New(t, WithPrefix("TestRealT"))
+	lg.ILog("visible with go test -v")
+}
+
+// testObj is a component that embeds a Logger, in the style of the TestObj used by the logger package's tests
+type testObj struct {
+	logger.Logger
+	id int
+}
+
+func newTestObj(lg logger.Logger, id int) *testObj {
+	return &testObj{Logger: lg.ForkLogf("TestObj %d", id), id: id}
+}
+
+func (o *testObj) retry(ms int) {
+	o.WLogw(fmt.Sprintf("retrying after %dms", ms), "attempt", 2)
+}
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder()
+	lg := rec.NewLogger("TestRecorder", logger.LogLevelDebug)
+	obj := newTestObj(lg, 2)
+
+	_, _, line, _ := runtime.Caller(0)
+	obj.retry(250)
+	obj.ILog("done")
+	obj.TLog("filtered")
+	lg.Printf("unconditional")
+
+	e := rec.AssertLogged(t, logger.LogLevelWarning, "TestObj 2", `retrying after \d+ms attempt=2`)
+	if e.Prefix() != "TestRecorder: TestObj 2" || e.Message != "retrying after 250ms" {
+		t.Errorf("Unexpected entry %v", e)
+	}
+	if v, ok := e.Field("attempt"); !ok || v != 2 {
+		t.Errorf("Expected field attempt=2; got %v", e.Fields)
+	}
+	e = rec.AssertLogged(t, logger.LogLevelInfo, "TestRecorder: TestObj 2", "^done$")
+	if !strings.HasSuffix(e.File, "loggertest_test.go") || e.Line != line+2 {
+		t.Errorf("Expected caller loggertest_test.go:%d; got %s:%d", line+2, e.File, e.Line)
+	}
+	rec.AssertLogged(t, logger.LogLevelUnknown, "", "unconditional")
+	rec.AssertNotLogged(t, logger.LogLevelTrace, "", "")
+	rec.AssertNotLogged(t, logger.LogLevelWarning, "TestObj 3", "")
+
+	if n := rec.Count(logger.LogLevelWarning); n != 1 {
+		t.Errorf("Expected 1 warning; got %d", n)
+	}
+	if n := len(rec.Entries()); n != 3 {
+		t.Errorf("Expected 3 entries; got %d", n)
+	}
+
+	// Failed assertions report the captured entries
+	tb := runFake(t, func(tb *fakeTB) {
+		rec.AssertLogged(tb, logger.LogLevelError, "", "")
+	})
+	if !strings.Contains(tb.buf.String(), "[warning] TestRecorder: TestObj 2: retrying after 250ms attempt=2") {
+		t.Errorf("Expected failure message to include captured entries; got %q", tb.buf.String())
+	}
+
+	rec.Reset()
+	if n := len(rec.Entries()); n != 0 {
+		t.Errorf("Expected no entries after Reset; got %d", n)
+	}
+
+	// A Recorder may also be used as a RawLogger
+	var raw logger.RawLogger = rec
+	raw.Output(1, "raw text\n")
+	e = rec.AssertLogged(t, logger.LogLevelUnknown, "", "^raw text$")
+	if !strings.HasSuffix(e.File, "loggertest_test.go") {
+		t.Errorf("Expected caller loggertest_test.go; got %s", e.File)
+	}
+}
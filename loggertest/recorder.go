@@ -0,0 +1,218 @@
+package loggertest
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sammck-go/logger"
+)
+
+// Entry is a single log event captured by a Recorder
+type Entry struct {
+	// Time is the time at which the event was logged
+	Time time.Time
+	// Level is the level at which the event was logged, or logger.LogLevelUnknown for unconditional output
+	Level logger.LogLevel
+	// PrefixPath contains the prefix segments of the logger that logged the event, outermost first
+	PrefixPath []string
+	// Message is the message text, without the prefix or fields
+	Message string
+	// Fields contains the structured fields of the event
+	Fields []logger.Field
+	// File is the source file of the logging call, or an empty string if unknown
+	File string
+	// Line is the source line of the logging call, or 0 if unknown
+	Line int
+}
+
+// Prefix returns the entry's prefix segments joined with ": "
+func (e Entry) Prefix() string {
+	return strings.Join(e.PrefixPath, ": ")
+}
+
+// Text returns the entry's message followed by its fields, formatted as " key=value" pairs
+func (e Entry) Text() string {
+	r := logger.Record{Message: e.Message, Fields: e.Fields}
+	return r.Text()
+}
+
+// Field returns the value of the last field in the entry with a given key, and whether the key was found
+func (e Entry) Field(key string) (interface{}, bool) {
+	for i := len(e.Fields) - 1; i >= 0; i-- {
+		if e.Fields[i].Key == key {
+			return e.Fields[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// String formats the entry for use in test failure messages
+func (e Entry) String() string {
+	caller := ""
+	if e.File != "" {
+		caller = fmt.Sprintf("%s:%d: ", filepath.Base(e.File), e.Line)
+	}
+	r := logger.Record{PrefixPath: e.PrefixPath, Message: e.Message, Fields: e.Fields}
+	return fmt.Sprintf("%s[%s] %s", caller, e.Level.String(), r.Text())
+}
+
+// Recorder is a logger.Handler and logger.RawLogger that captures log events in memory, so that tests can
+// make assertions about what was logged. It may be used from multiple goroutines.
+//
+//	rec := loggertest.NewRecorder()
+//	obj := NewTestObj(rec.NewLogger("TestLogging", logger.LogLevelDebug), 2)
+//	obj.DoSomething()
+//	rec.AssertLogged(t, logger.LogLevelWarning, "TestObj 2", `retrying after \d+ms`)
+type Recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewRecorder creates a new, empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// NewLogger creates a Logger with an optional prefix and a loglevel that writes to the Recorder
+func (rec *Recorder) NewLogger(prefix string, logLevel logger.LogLevel) logger.Logger {
+	return logger.NewWithHandler(rec, prefix, logLevel)
+}
+
+// Handle captures a Record
+func (rec *Recorder) Handle(r *logger.Record) error {
+	e := Entry{
+		Time:       r.Time,
+		Level:      r.Level,
+		PrefixPath: append([]string(nil), r.PrefixPath...),
+		Message:    strings.TrimSuffix(r.Message, "\n"),
+		Fields:     append([]logger.Field(nil), r.Fields...),
+	}
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		e.File = frame.File
+		e.Line = frame.Line
+	}
+	rec.add(e)
+	return nil
+}
+
+// Output captures a line of unstructured text, so that the Recorder can be used as a RawLogger. The entry's
+// level is logger.LogLevelUnknown, and its message is s.
+func (rec *Recorder) Output(calldepth int, s string) error {
+	e := Entry{
+		Time:    time.Now(),
+		Level:   logger.LogLevelUnknown,
+		Message: strings.TrimSuffix(s, "\n"),
+	}
+	_, file, line, ok := runtime.Caller(calldepth)
+	if ok {
+		e.File = file
+		e.Line = line
+	}
+	rec.add(e)
+	return nil
+}
+
+// add appends an entry
+func (rec *Recorder) add(e Entry) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.entries = append(rec.entries, e)
+}
+
+// Entries returns a copy of the captured entries, in the order in which they were logged
+func (rec *Recorder) Entries() []Entry {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]Entry(nil), rec.entries...)
+}
+
+// Reset discards all captured entries
+func (rec *Recorder) Reset() {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.entries = nil
+}
+
+// Count returns the number of captured entries at a given level
+func (rec *Recorder) Count(logLevel logger.LogLevel) int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	n := 0
+	for _, e := range rec.entries {
+		if e.Level == logLevel {
+			n++
+		}
+	}
+	return n
+}
+
+// Matching returns the captured entries at a given level whose prefix matches prefix, and whose text (see
+// Entry.Text) matches the regular expression pattern. An empty prefix matches any entry; otherwise, prefix
+// matches an entry if it is equal to the entry's full prefix, or to any one segment of its prefix path.
+// An empty pattern matches any text. Matching panics if pattern is not a valid regular expression.
+func (rec *Recorder) Matching(logLevel logger.LogLevel, prefix string, pattern string) []Entry {
+	re := regexp.MustCompile(pattern)
+	var result []Entry
+	for _, e := range rec.Entries() {
+		if e.Level == logLevel && prefixMatches(e, prefix) && re.MatchString(e.Text()) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// prefixMatches returns true if prefix is empty, or is equal to the entry's full prefix or to one of its
+// prefix segments
+func prefixMatches(e Entry, prefix string) bool {
+	if prefix == "" || prefix == e.Prefix() {
+		return true
+	}
+	for _, segment := range e.PrefixPath {
+		if segment == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// AssertLogged fails the test if no captured entry matches logLevel, prefix and pattern (see Matching), and
+// returns the first matching entry. On failure, the captured entries are included in the failure message.
+func (rec *Recorder) AssertLogged(t testing.TB, logLevel logger.LogLevel, prefix string, pattern string) Entry {
+	t.Helper()
+	matches := rec.Matching(logLevel, prefix, pattern)
+	if len(matches) == 0 {
+		t.Errorf("Expected an entry at level %s with prefix %q matching %q; got:%s", logLevel.String(), prefix, pattern, rec.dump())
+		return Entry{}
+	}
+	return matches[0]
+}
+
+// AssertNotLogged fails the test if any captured entry matches logLevel, prefix and pattern (see Matching)
+func (rec *Recorder) AssertNotLogged(t testing.TB, logLevel logger.LogLevel, prefix string, pattern string) {
+	t.Helper()
+	matches := rec.Matching(logLevel, prefix, pattern)
+	if len(matches) > 0 {
+		t.Errorf("Expected no entry at level %s with prefix %q matching %q; got:%s", logLevel.String(), prefix, pattern, rec.dump())
+	}
+}
+
+// dump formats the captured entries for a failure message, one per line
+func (rec *Recorder) dump() string {
+	entries := rec.Entries()
+	if len(entries) == 0 {
+		return " no entries"
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		b.WriteString("\n\t")
+		b.WriteString(e.String())
+	}
+	return b.String()
+}
@@ -0,0 +1,15 @@
+module github.com/sammck-go/logger/logradapter
+
+go 1.23
+
+require (
+	github.com/go-logr/logr v1.4.4
+	github.com/sammck-go/logger v0.0.0-20261017014416-8404c22dd9ce
+)
+
+require (
+	github.com/spf13/pflag v1.0.5 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+)
+
+replace github.com/sammck-go/logger => ../
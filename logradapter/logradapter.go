@@ -0,0 +1,96 @@
+/*
+Package logradapter implements a logr.LogSink on top of a logger.Logger, so that libraries that require a
+logr.Logger (such as Kubernetes controller-runtime) write to the same stream, with the same prefixes, as the
+rest of an application.
+*/
+package logradapter
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/sammck-go/logger"
+)
+
+// ErrorKey is the key of the structured field that holds the error passed to logr.Logger.Error
+const ErrorKey = "error"
+
+// LogSink is a logr.LogSink that writes to a logger.Logger. logr verbosity levels are mapped onto
+// LogLevels with LogLevelFromV, WithName forks a new prefix segment with ForkLogStr, and WithValues
+// adds structured fields with ForkWith.
+type LogSink struct {
+	lg        logger.Logger
+	callDepth int
+}
+
+var _ logr.LogSink = &LogSink{}
+var _ logr.CallDepthLogSink = &LogSink{}
+
+// NewLogSink creates a logr.LogSink that writes to a logger.Logger
+func NewLogSink(lg logger.Logger) *LogSink {
+	s := &LogSink{
+		lg: lg,
+	}
+	return s
+}
+
+// New creates a logr.Logger that writes to a logger.Logger
+func New(lg logger.Logger) logr.Logger {
+	return logr.New(NewLogSink(lg))
+}
+
+// LogLevelFromV converts a logr verbosity level to a LogLevel. V(0) maps to LogLevelInfo, V(1) to
+// LogLevelDebug, and V(2) and above to LogLevelTrace.
+func LogLevelFromV(level int) logger.LogLevel {
+	switch {
+	case level <= 0:
+		return logger.LogLevelInfo
+	case level == 1:
+		return logger.LogLevelDebug
+	default:
+		return logger.LogLevelTrace
+	}
+}
+
+// Logger returns the logger.Logger that the LogSink writes to
+func (s *LogSink) Logger() logger.Logger {
+	return s.lg
+}
+
+// Init receives runtime information from logr.Logger
+func (s *LogSink) Init(info logr.RuntimeInfo) {
+	s.callDepth += info.CallDepth
+}
+
+// Enabled reports whether the Logger's level enables messages at the given verbosity level
+func (s *LogSink) Enabled(level int) bool {
+	return LogLevelFromV(level) <= s.lg.GetLogLevel()
+}
+
+// Info logs a non-error message at the LogLevel corresponding to the given verbosity level, with
+// keysAndValues as structured fields
+func (s *LogSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.lg.CdLogw(s.callDepth+2, LogLevelFromV(level), msg, keysAndValues...)
+}
+
+// Error logs an error message at LogLevelError, with the error as a structured field with key ErrorKey
+// followed by keysAndValues
+func (s *LogSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	kvs := make([]interface{}, 0, len(keysAndValues)+1)
+	kvs = append(kvs, logger.F(ErrorKey, err))
+	kvs = append(kvs, keysAndValues...)
+	s.lg.CdLogw(s.callDepth+2, logger.LogLevelError, msg, kvs...)
+}
+
+// WithValues returns a new LogSink whose Logger has keysAndValues added as structured fields
+func (s *LogSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &LogSink{lg: s.lg.ForkWith(keysAndValues...), callDepth: s.callDepth}
+}
+
+// WithName returns a new LogSink whose Logger has name added as a prefix segment
+func (s *LogSink) WithName(name string) logr.LogSink {
+	return &LogSink{lg: s.lg.ForkLogStr(name), callDepth: s.callDepth}
+}
+
+// WithCallDepth returns a new LogSink that attributes messages to a caller depth frames further up the stack
+func (s *LogSink) WithCallDepth(depth int) logr.LogSink {
+	return &LogSink{lg: s.lg, callDepth: s.callDepth + depth}
+}
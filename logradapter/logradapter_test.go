@@ -0,0 +1,222 @@
+package logradapter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/sammck-go/logger"
+)
+
+func newTestLogr(level logger.LogLevel) (logr.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	lg := logger.NewLogWrapper(log.New(&buf, "", log.Lshortfile), "TestLogr", level)
+	return New(lg), &buf
+}
+
+func checkLines(t *testing.T, buf *bytes.Buffer, expectedTails []string) {
+	t.Helper()
+	output := strings.TrimSuffix(buf.String(), "\n")
+	lines := []string{}
+	if output != "" {
+		lines = strings.Split(output, "\n")
+	}
+	if len(lines) != len(expectedTails) {
+		t.Fatalf("Expected %d lines; got %q", len(expectedTails), buf.String())
+	}
+	for i, tail := range expectedTails {
+		if !strings.HasPrefix(lines[i], "logradapter_test.go:") || !strings.HasSuffix(lines[i], ": "+tail) {
+			t.Errorf("Expected [logradapter_test.go:<line>: %s]; got [%s]", tail, lines[i])
+		}
+	}
+	buf.Reset()
+}
+
+func TestVerbosity(t *testing.T) {
+	for _, tc := range []struct {
+		level   logger.LogLevel
+		enabled []bool
+	}{
+		{logger.LogLevelWarning, []bool{false, false, false}},
+		{logger.LogLevelInfo, []bool{true, false, false}},
+		{logger.LogLevelDebug, []bool{true, true, false}},
+		{logger.LogLevelTrace, []bool{true, true, true}},
+	} {
+		lr, buf := newTestLogr(tc.level)
+		expectedTails := []string{}
+		for v, enabled := range tc.enabled {
+			if lr.V(v).Enabled() != enabled {
+				t.Errorf("At level %v, expected V(%d).Enabled() to be %v", tc.level, v, enabled)
+			}
+			lr.V(v).Info("verbose", "v", v)
+			if enabled {
+				expectedTails = append(expectedTails, fmt.Sprintf("TestLogr: verbose v=%d", v))
+			}
+		}
+		// Errors are logged regardless of verbosity, as long as LogLevelError is enabled
+		lr.V(2).Error(errors.New("oops"), "failed")
+		expectedTails = append(expectedTails, "TestLogr: failed error=oops")
+		checkLines(t, buf, expectedTails)
+	}
+}
+
+func TestNamesAndValues(t *testing.T) {
+	lr, buf := newTestLogr(logger.LogLevelInfo)
+
+	child := lr.WithName("controller").WithValues("kind", "Pod").WithName("reconcile")
+	child.Info("started", "name", "web-0")
+	child.Error(errors.New("not found"), "lookup failed", "namespace", "default")
+	lr.WithValues("odd").Info("bad key")
+	// Deriving a child does not affect the parent
+	lr.Info("parent")
+
+	checkLines(t, buf, []string{
+		"TestLogr: controller: reconcile: started kind=Pod name=web-0",
+		"TestLogr: controller: reconcile: lookup failed kind=Pod error=\"not found\" namespace=default",
+		"TestLogr: bad key !BADKEY=odd",
+		"TestLogr: parent",
+	})
+}
+
+func TestCallDepth(t *testing.T) {
+	lr, buf := newTestLogr(logger.LogLevelInfo)
+
+	helper := func(msg string) {
+		lr.WithCallDepth(1).Info(msg)
+	}
+	_, _, line, _ := runtime.Caller(0)
+	lr.Info("direct")
+	helper("via helper")
+
+	lines := strings.Split(buf.String(), "\n")
+	for i, expected := range []string{
+		fmt.Sprintf("logradapter_test.go:%d: TestLogr: direct", line+1),
+		fmt.Sprintf("logradapter_test.go:%d: TestLogr: via helper", line+2),
+	} {
+		if lines[i] != expected {
+			t.Errorf("Expected [%s]; got [%s]", expected, lines[i])
+		}
+	}
+}
+
+func TestExtremeVerbosity(t *testing.T) {
+	for _, tc := range []struct {
+		level   logger.LogLevel
+		v       int
+		enabled bool
+	}{
+		{logger.LogLevelInfo, -1, true},
+		{logger.LogLevelInfo, math.MinInt32, true},
+		{logger.LogLevelWarning, -1, false},
+		{logger.LogLevelDebug, math.MaxInt32, false},
+		{logger.LogLevelTrace, math.MaxInt32, true},
+		{logger.LogLevelTrace, math.MaxInt, true},
+	} {
+		lr, buf := newTestLogr(tc.level)
+		if lr.V(tc.v).Enabled() != tc.enabled {
+			t.Errorf("At level %v, expected V(%d).Enabled() to be %v", tc.level, tc.v, tc.enabled)
+		}
+		if NewLogSink(logger.NewLogWrapper(log.New(buf, "", 0), "", tc.level)).Enabled(tc.v) != tc.enabled {
+			t.Errorf("At level %v, expected LogSink.Enabled(%d) to be %v", tc.level, tc.v, tc.enabled)
+		}
+		lr.V(tc.v).Info("verbose")
+		expectedTails := []string{}
+		if tc.enabled {
+			expectedTails = append(expectedTails, "TestLogr: verbose")
+		}
+		checkLines(t, buf, expectedTails)
+	}
+}
+
+func TestErrorAtEveryVerbosity(t *testing.T) {
+	for _, level := range []logger.LogLevel{logger.LogLevelError, logger.LogLevelWarning, logger.LogLevelInfo, logger.LogLevelTrace} {
+		lr, buf := newTestLogr(level)
+		expectedTails := []string{}
+		for _, v := range []int{-1, 0, 1, 2, 3, math.MaxInt32} {
+			lr.V(v).Error(errors.New("oops"), "failed", "v", v)
+			expectedTails = append(expectedTails, fmt.Sprintf("TestLogr: failed error=oops v=%d", v))
+		}
+		checkLines(t, buf, expectedTails)
+	}
+
+	// Errors are not emitted if LogLevelError itself is disabled
+	lr, buf := newTestLogr(logger.LogLevelFatal)
+	lr.Error(errors.New("oops"), "failed")
+	checkLines(t, buf, []string{})
+}
+
+func TestMalformedKeysAndValues(t *testing.T) {
+	lr, buf := newTestLogr(logger.LogLevelInfo)
+
+	lr.Info("odd", "a", 1, "b")
+	lr.Info("non-string key", 42, "x", "k", "v")
+	lr.Error(nil, "nil error", "dangling")
+	lr.WithValues(3.5, true).Info("non-string values key")
+	lr.WithValues("a", 1, "b").WithValues("c", 2).Info("odd values")
+
+	checkLines(t, buf, []string{
+		"TestLogr: odd a=1 !BADKEY=b",
+		"TestLogr: non-string key !BADKEY=42 x=k !BADKEY=v",
+		"TestLogr: nil error error=<nil> !BADKEY=dangling",
+		"TestLogr: non-string values key !BADKEY=3.5 !BADKEY=true",
+		"TestLogr: odd values a=1 !BADKEY=b c=2",
+	})
+}
+
+func TestConcurrentDerivedLoggers(t *testing.T) {
+	lr, buf := newTestLogr(logger.LogLevelInfo)
+	base := lr.WithName("base").WithValues("shared", 0)
+
+	const goroutines = 8
+	const iterations = 50
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				base.WithName(fmt.Sprintf("g%d", g)).WithValues("g", g).Info("tick", "i", i)
+				base.WithValues("i", i).V(0).Info("plain")
+			}
+		}(g)
+	}
+	wg.Wait()
+	base.Info("after")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2*goroutines*iterations+1 {
+		t.Fatalf("Expected %d lines; got %d", 2*goroutines*iterations+1, len(lines))
+	}
+	counts := map[string]int{}
+	for _, line := range lines[:len(lines)-1] {
+		i := strings.Index(line, ": TestLogr: base: ")
+		if i < 0 {
+			t.Fatalf("Unexpected line [%s]", line)
+		}
+		rest := line[i+len(": TestLogr: base: "):]
+		var g, n int
+		if _, err := fmt.Sscanf(rest, "g%d: tick shared=0 g=%d i=%d", &g, new(int), &n); err == nil {
+			if !strings.HasSuffix(rest, fmt.Sprintf("tick shared=0 g=%d i=%d", g, n)) {
+				t.Errorf("Values leaked between derived loggers: [%s]", line)
+			}
+			counts["tick"]++
+		} else if _, err := fmt.Sscanf(rest, "plain shared=0 i=%d", &n); err == nil && strings.Count(rest, "=") == 2 {
+			counts["plain"]++
+		} else {
+			t.Errorf("Unexpected line [%s]", line)
+		}
+	}
+	if counts["tick"] != goroutines*iterations || counts["plain"] != goroutines*iterations {
+		t.Errorf("Unexpected line counts %v", counts)
+	}
+	if !strings.HasSuffix(lines[len(lines)-1], ": TestLogr: base: after shared=0") {
+		t.Errorf("Base logger changed by derived loggers: [%s]", lines[len(lines)-1])
+	}
+}
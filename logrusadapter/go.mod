@@ -0,0 +1,15 @@
+module github.com/sammck-go/logger/logrusadapter
+
+go 1.21
+
+require (
+	github.com/sammck-go/logger v0.0.0-20261017014416-8404c22dd9ce
+	github.com/sirupsen/logrus v1.9.4
+)
+
+require (
+	github.com/spf13/pflag v1.0.5 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+)
+
+replace github.com/sammck-go/logger => ../
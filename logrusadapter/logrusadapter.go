@@ -0,0 +1,170 @@
+/*
+Package logrusadapter connects a logger.Logger with github.com/sirupsen/logrus in both directions. Hook and New
+expose a logger.Logger as a logrus.Hook or *logrus.Logger, for dependencies that expect logrus; NewHandler and
+NewLogger write the output of a logger.Logger to an existing logrus.FieldLogger.
+
+Logrus has no equivalent of logger prefixes, so prefixes are carried in a field with key PrefixKey.
+*/
+package logrusadapter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/sammck-go/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// PrefixKey is the key of the logrus field that carries a logger prefix
+const PrefixKey = "prefix"
+
+// LogLevelFromLogrus converts a logrus.Level to a LogLevel
+func LogLevelFromLogrus(level logrus.Level) logger.LogLevel {
+	switch level {
+	case logrus.PanicLevel:
+		return logger.LogLevelPanic
+	case logrus.FatalLevel:
+		return logger.LogLevelFatal
+	case logrus.ErrorLevel:
+		return logger.LogLevelError
+	case logrus.WarnLevel:
+		return logger.LogLevelWarning
+	case logrus.InfoLevel:
+		return logger.LogLevelInfo
+	case logrus.DebugLevel:
+		return logger.LogLevelDebug
+	default:
+		return logger.LogLevelTrace
+	}
+}
+
+// LogLevelToLogrus converts a LogLevel to a logrus.Level. LogLevelUnknown maps to logrus.InfoLevel.
+func LogLevelToLogrus(logLevel logger.LogLevel) logrus.Level {
+	switch logLevel {
+	case logger.LogLevelPanic:
+		return logrus.PanicLevel
+	case logger.LogLevelFatal:
+		return logrus.FatalLevel
+	case logger.LogLevelError:
+		return logrus.ErrorLevel
+	case logger.LogLevelWarning:
+		return logrus.WarnLevel
+	case logger.LogLevelDebug:
+		return logrus.DebugLevel
+	case logger.LogLevelTrace:
+		return logrus.TraceLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
+
+// Hook is a logrus.Hook that writes logrus entries to a logger.Logger. Entries are filtered by the Logger's
+// current level, the field with key PrefixKey (if any) becomes a prefix segment, and the other fields become
+// structured fields, sorted by key. The caller is only preserved if the logrus.Logger has ReportCaller set.
+type Hook struct {
+	lg logger.Logger
+}
+
+// NewHook creates a logrus.Hook that writes logrus entries to a logger.Logger
+func NewHook(lg logger.Logger) *Hook {
+	h := &Hook{
+		lg: lg,
+	}
+	return h
+}
+
+// nopFormatter is a logrus.Formatter that produces no output
+type nopFormatter struct{}
+
+// Format returns no output
+func (f nopFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	return nil, nil
+}
+
+// New creates a *logrus.Logger whose only output is a Hook that writes to a logger.Logger. The logrus level
+// is set to logrus.TraceLevel, so that filtering is determined by the Logger's current level.
+func New(lg logger.Logger) *logrus.Logger {
+	l := logrus.New()
+	l.Out = io.Discard
+	l.Formatter = nopFormatter{}
+	l.Level = logrus.TraceLevel
+	l.ReportCaller = true
+	l.AddHook(NewHook(lg))
+	return l
+}
+
+// Levels returns all logrus levels
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire writes a logrus entry to the Logger
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	r := &logger.Record{
+		Time:    entry.Time,
+		Level:   LogLevelFromLogrus(entry.Level),
+		Message: entry.Message,
+	}
+	if entry.Caller != nil {
+		r.PC = entry.Caller.PC
+	}
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		if k == PrefixKey {
+			r.PrefixPath = []string{fmt.Sprint(entry.Data[k])}
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		r.Fields = append(r.Fields, logger.F(k, entry.Data[k]))
+	}
+	return logger.LogRecord(h.lg, r)
+}
+
+// handler is a logger.Handler that writes Records to a logrus.FieldLogger
+type handler struct {
+	fl logrus.FieldLogger
+}
+
+// NewHandler creates a logger.Handler that writes each Record to a logrus.FieldLogger, such as a
+// *logrus.Logger or *logrus.Entry. The level is converted with LogLevelToLogrus, the prefix is passed as a
+// field with key PrefixKey, and fields are passed as logrus fields. Records at levels that the logrus logger
+// does not enable are discarded. Records at LogLevelPanic and LogLevelFatal are written without panicking
+// or exiting. Note that the caller reported by logrus with ReportCaller is not the logging call site.
+func NewHandler(fl logrus.FieldLogger) logger.Handler {
+	return &handler{fl: fl}
+}
+
+// NewLogger creates a new Logger that writes to a logrus.FieldLogger, with an optional prefix and a loglevel.
+// See NewHandler.
+func NewLogger(fl logrus.FieldLogger, prefix string, logLevel logger.LogLevel) logger.Logger {
+	return logger.NewWithHandler(NewHandler(fl), prefix, logLevel)
+}
+
+// Handle converts a Record to a logrus entry and logs it
+func (h *handler) Handle(r *logger.Record) error {
+	fields := make(logrus.Fields, len(r.Fields)+1)
+	for _, f := range r.Fields {
+		fields[f.Key] = f.Value
+	}
+	if len(r.PrefixPath) > 0 {
+		fields[PrefixKey] = r.Prefix()
+	}
+	level := LogLevelToLogrus(r.Level)
+	if level == logrus.PanicLevel {
+		// logrus panics with the *logrus.Entry after logging at PanicLevel; the Logger panics itself after the
+		// record is handled. Other panics, e.g. from a hook, are propagated.
+		defer func() {
+			if v := recover(); v != nil {
+				if _, ok := v.(*logrus.Entry); !ok {
+					panic(v)
+				}
+			}
+		}()
+	}
+	h.fl.WithFields(fields).WithTime(r.Time).Log(level, r.Message)
+	return nil
+}
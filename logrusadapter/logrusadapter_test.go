@@ -0,0 +1,110 @@
+package logrusadapter
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sammck-go/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestHook(t *testing.T) {
+	var buf bytes.Buffer
+	lg := logger.NewLogWrapper(log.New(&buf, "", log.Lshortfile), "svc", logger.LogLevelDebug)
+	l := New(lg)
+
+	_, _, line, _ := runtime.Caller(0)
+	l.WithFields(logrus.Fields{"table": "users", "conn_id": 42, PrefixKey: "db"}).Info("opened")
+	l.Debugf("debug %s", "enabled")
+	lg.SetLogLevel(logger.LogLevelInfo)
+	l.Debug("debug disabled")
+	l.WithError(fmt.Errorf("timeout")).Warn("slow")
+	func() {
+		defer func() { recover() }()
+		l.Panic("boom")
+	}()
+
+	expectedLines := []string{
+		fmt.Sprintf("logrusadapter_test.go:%d: svc: db: opened conn_id=42 table=users", line+1),
+		fmt.Sprintf("logrusadapter_test.go:%d: svc: debug enabled", line+2),
+		fmt.Sprintf("logrusadapter_test.go:%d: svc: slow error=timeout", line+5),
+		fmt.Sprintf("logrusadapter_test.go:%d: svc: boom", line+8),
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(expectedLines) {
+		t.Fatalf("Expected %d lines; got %q", len(expectedLines), buf.String())
+	}
+	for i, expected := range expectedLines {
+		if lines[i] != expected {
+			t.Errorf("Expected [%s]; got [%s]", expected, lines[i])
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	l, hook := test.NewNullLogger()
+	l.Level = logrus.DebugLevel
+	lg := NewLogger(l, "svc", logger.LogLevelTrace)
+
+	lg.ForkLogStr("db").ForkWith("conn_id", 42).ILogf("opened %d", 3)
+	lg.TLog("filtered by the logrus logger")
+	lg.WLogw("slow", "ms", 250)
+	func() {
+		defer func() { recover() }()
+		lg.Panic("boom")
+	}()
+
+	expected := []struct {
+		level   logrus.Level
+		message string
+		fields  logrus.Fields
+	}{
+		{logrus.InfoLevel, "opened 3", logrus.Fields{PrefixKey: "svc: db", "conn_id": 42}},
+		{logrus.WarnLevel, "slow", logrus.Fields{PrefixKey: "svc", "ms": 250}},
+		{logrus.PanicLevel, "boom", logrus.Fields{PrefixKey: "svc"}},
+	}
+	entries := hook.AllEntries()
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries; got %d", len(expected), len(entries))
+	}
+	for i, e := range expected {
+		entry := entries[i]
+		if entry.Level != e.level || entry.Message != e.message || fmt.Sprint(entry.Data) != fmt.Sprint(e.fields) {
+			t.Errorf("Expected %v %q %v; got %v %q %v", e.level, e.message, e.fields, entry.Level, entry.Message, entry.Data)
+		}
+	}
+
+	// Panics other than the one logrus raises at PanicLevel are propagated
+	l.AddHook(panicHook{})
+	v := func() (v interface{}) {
+		defer func() { v = recover() }()
+		lg.Panic("boom")
+		return nil
+	}()
+	if v != "hook failed" {
+		t.Errorf("Expected the hook's panic; got %v", v)
+	}
+
+	for _, logLevel := range []logger.LogLevel{logger.LogLevelPanic, logger.LogLevelFatal, logger.LogLevelError,
+		logger.LogLevelWarning, logger.LogLevelInfo, logger.LogLevelDebug, logger.LogLevelTrace} {
+		if LogLevelFromLogrus(LogLevelToLogrus(logLevel)) != logLevel {
+			t.Errorf("Level %v did not survive conversion to and from logrus", logLevel)
+		}
+	}
+}
+
+// panicHook is a logrus.Hook that panics
+type panicHook struct{}
+
+func (panicHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (panicHook) Fire(*logrus.Entry) error {
+	panic("hook failed")
+}
@@ -10,6 +10,10 @@ func (l *nilRawLogger) Output(calldepth int, s string) error {
 	return nil
 }
 
+func (l *nilRawLogger) Handle(r *Record) error {
+	return nil
+}
+
 func (l *nilRawLogger) GetLogLevel() LogLevel {
 	return LogLevelFatal
 }
@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Record is a single log event, as emitted by a BasicLogger to its Handler. Records are
+// created fresh for each event, and may be retained by a Handler after Handle returns; however,
+// the PrefixPath and Fields slices may be shared with the emitting logger and must not be modified.
+type Record struct {
+	// Time is the time at which the event was logged
+	Time time.Time
+
+	// Level is the level at which the event was logged. It is LogLevelUnknown for unconditional
+	// output such as Print, Printf and CdRawOutput.
+	Level LogLevel
+
+	// PrefixPath contains the prefix segments added by each successive fork of the emitting
+	// logger, outermost first. It is empty for output that does not include the logger's prefix.
+	PrefixPath []string
+
+	// Message is the formatted message text, without the prefix or fields
+	Message string
+
+	// PC is the program counter of the logging call site, suitable for use with runtime.CallersFrames.
+	// It is zero if unknown.
+	PC uintptr
+
+	// Fields contains the structured key/value fields of the emitting logger, followed by any fields
+	// provided with the event itself
+	Fields []Field
+
+	// CallDepth is the number of stack frames between the Handle method receiving the record
+	// and the logging call site. A Handle method that passes the record on to RawLogger.Output
+	// should pass CallDepth+1. A Handler that forwards the record synchronously to another
+	// Handler should increment CallDepth on a copy of the record. It is not meaningful after
+	// Handle returns.
+	CallDepth int
+}
+
+// Prefix returns the record's prefix segments joined with ": " (does not include ": " trailer)
+func (r *Record) Prefix() string {
+	return strings.Join(r.PrefixPath, ": ")
+}
+
+// Text returns the traditional single-line rendering of a record: the prefix and ": " (if there is a prefix),
+// the message, and the fields formatted as " key=value" pairs. It does not include a time or level.
+func (r *Record) Text() string {
+	return string(r.AppendText(nil))
+}
+
+// AppendText appends the result of Text to b, and returns the extended buffer
+func (r *Record) AppendText(b []byte) []byte {
+	for _, segment := range r.PrefixPath {
+		b = append(b, segment...)
+		b = append(b, ": "...)
+	}
+	b = append(b, r.Message...)
+	return appendFields(b, r.Fields)
+}
+
+// LogRecord logs a Record that originated outside of this package, such as an event received by an
+// adapter from another logging library, to a Logger if the record's level is enabled. The Logger's
+// prefix and fields are prepended to the record's PrefixPath and Fields. A zero Time is replaced with
+// the current time. Records at LogLevelPanic and LogLevelFatal do not exit or panic.
+//
+// If the record's PC is set, the record's CallDepth is computed by locating the PC on the current stack,
+// so that handlers that write to a RawLogger report the correct caller; otherwise CallDepth refers to the
+// caller of LogRecord.
+//
+// If lg is not a *BasicLogger, the record is logged with CdLogw, and its Time and PC are not preserved.
+func LogRecord(lg Logger, r *Record) error {
+	if r.Level > lg.GetLogLevel() && r.Level > LogLevelFatal {
+		return nil
+	}
+	callDepth := 2
+	if r.PC != 0 {
+		var pcs [64]uintptr
+		n := runtime.Callers(2, pcs[:])
+		for i := 0; i < n; i++ {
+			// Frame.PC values from runtime.CallersFrames may refer to the call instruction rather than
+			// the return address
+			if pcs[i] == r.PC || pcs[i]-1 == r.PC {
+				callDepth = i + 2
+				break
+			}
+		}
+	}
+
+	bl, ok := lg.(*BasicLogger)
+	if !ok {
+		logLevel := r.Level
+		if logLevel <= LogLevelFatal && logLevel != LogLevelUnknown {
+			logLevel = LogLevelError
+		}
+		for _, segment := range r.PrefixPath {
+			lg = lg.ForkLogStr(segment)
+		}
+		kvs := make([]interface{}, len(r.Fields))
+		for i, f := range r.Fields {
+			kvs[i] = f
+		}
+		lg.CdLogw(callDepth, logLevel, r.Message, kvs...)
+		return nil
+	}
+
+	rr := *r
+	if rr.Time.IsZero() {
+		rr.Time = time.Now()
+	}
+	rr.CallDepth = callDepth
+	return bl.Handle(&rr)
+}
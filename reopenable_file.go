@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// ReopenableFile is an io.WriteCloser that appends to a file, and can reopen the file by path on request or on
+// receipt of a signal. This allows an external tool such as logrotate to rename the file and then signal the
+// process (e.g., with "postrotate kill -HUP"), after which output continues in a new file at the original path.
+//
+// Each call to Write is appended to a single file in its entirety, and Write may be called from multiple
+// goroutines, so no record is lost or split while the file is reopened. Records written before the reopen
+// completes are appended to the renamed file. See WithReopenableLogFile.
+type ReopenableFile struct {
+	path string
+	// onError reports errors from reopening on a signal; if nil, they are written to stderr. It is set by
+	// NewWithConfig before the ReopenableFile is shared.
+	onError func(err error)
+
+	mu     sync.Mutex
+	file   *os.File
+	closed bool
+}
+
+// OpenReopenableFile opens a ReopenableFile, appending to the file at path, which is created if it does not exist
+func OpenReopenableFile(path string) (*ReopenableFile, error) {
+	file, err := openAppend(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &ReopenableFile{
+		path: path,
+		file: file,
+	}
+	return f, nil
+}
+
+// openAppend opens a file for appending, creating it if it does not exist
+func openAppend(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+}
+
+// Write appends p to the file
+func (f *ReopenableFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	return f.file.Write(p)
+}
+
+// Reopen opens the file at the ReopenableFile's path, which is created if it does not exist, and directs
+// subsequent writes to it. The previously open file is closed. If the file cannot be opened, writes continue
+// to the previously open file and an error is returned.
+func (f *ReopenableFile) Reopen() error {
+	file, err := openAppend(f.path)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		file.Close()
+		return os.ErrClosed
+	}
+	old := f.file
+	f.file = file
+	f.mu.Unlock()
+	return old.Close()
+}
+
+// ReopenOnSignal starts a goroutine that calls Reopen whenever the process receives one of the given signals,
+// e.g., syscall.SIGHUP. Errors from Reopen are counted in the Stats of the logger that opened the file, if it was
+// opened by WithReopenableLogFile (see BasicLogger.Stats), and otherwise reported on stderr. The returned function
+// stops the goroutine and stops relaying the signals.
+func (f *ReopenableFile) ReopenOnSignal(sigs ...os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				err := f.Reopen()
+				if err != nil && err != os.ErrClosed {
+					err = fmt.Errorf("unable to reopen %s: %w", f.path, err)
+					if f.onError != nil {
+						f.onError(err)
+					} else {
+						fmt.Fprintf(os.Stderr, "logger: %s\n", err)
+					}
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// Close closes the file. Subsequent writes fail with os.ErrClosed.
+func (f *ReopenableFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	return f.file.Close()
+}
+
+// WithReopenableLogFile causes log output to be appended to a ReopenableFile with a given path, which is created
+// if it does not exist. The file is opened by NewWithConfig. If signals are provided, such as syscall.SIGHUP,
+// the file is reopened whenever the process receives one of them, for the life of the process. This setting
+// replaces any prior effect of WithWriter(), WithLogger(), WithHandler(), WithLogFile() or WithRotatingFile().
+func WithReopenableLogFile(path string, sigs ...os.Signal) ConfigOption {
+	return func(cfg *Config) {
+		cfg.logFile = path
+		cfg.rotateOpts = nil
+		cfg.reopenable = true
+		cfg.reopenSignals = sigs
+		cfg.logWriter = nil
+		cfg.parentLogger = nil
+		cfg.handler = nil
+	}
+}
@@ -0,0 +1,453 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateInterval selects periodic rotation of a RotatingFile
+type RotateInterval int
+
+const (
+	// RotateNever disables periodic rotation. This is the default.
+	RotateNever RotateInterval = iota
+
+	// RotateHourly rotates at the start of every hour
+	RotateHourly RotateInterval = iota
+
+	// RotateDaily rotates at midnight
+	RotateDaily RotateInterval = iota
+)
+
+// segmentTimeLayout is the layout of the timestamp in segment file names
+const segmentTimeLayout = "20060102T150405"
+
+// RotateOptions configures a RotatingFile
+type RotateOptions struct {
+	// MaxSize is the size in bytes at which the current segment is rotated. A single write is never split
+	// between segments, so a segment may exceed MaxSize by up to the size of one record. Zero disables
+	// size-based rotation.
+	MaxSize int64
+
+	// Interval selects periodic rotation
+	Interval RotateInterval
+
+	// MaxBackups is the maximum number of rotated segments to keep. Zero keeps all segments.
+	MaxBackups int
+
+	// MaxAge is the maximum age of rotated segments to keep, based on their modification time. Zero keeps
+	// all segments.
+	MaxAge time.Duration
+
+	// Compress causes rotated segments to be compressed with gzip in the background. Compression errors are
+	// counted in the Stats of the logger that opened the file, if it was opened by WithRotatingFile (see
+	// BasicLogger.Stats), and otherwise reported on stderr.
+	Compress bool
+
+	// Symlink causes a symbolic link to the current segment to be maintained at the RotatingFile's path
+	Symlink bool
+
+	// UTC causes segment names and rotation intervals to use UTC rather than local time
+	UTC bool
+}
+
+// RotatingFile is an io.WriteCloser that writes to a series of files ("segments"), rotating to a new segment
+// when the current one reaches a maximum size or at hourly or daily intervals. Segment names are derived from
+// the RotatingFile's path by inserting the time at which the segment was created before the extension; e.g.,
+// with path "/var/log/app.log", segments are named "/var/log/app-20060102T150405.log". If two segments are
+// created within the same second, a sequence number is added, e.g., "app-20060102T150405-1.log".
+// Rotated segments may be compressed, and old segments are removed according to MaxBackups and MaxAge.
+//
+// Each call to Write is appended to the current segment in its entirety, and Write may be called from multiple
+// goroutines, so a RotatingFile can be shared by many loggers. See WithRotatingFile.
+type RotatingFile struct {
+	path string
+	opts RotateOptions
+	// now returns the current time; it may be replaced by tests
+	now func() time.Time
+	// onError reports errors from background compression; if nil, they are written to stderr. It is set by
+	// NewWithConfig before the RotatingFile is shared.
+	onError func(err error)
+
+	mu         sync.Mutex
+	file       *os.File
+	name       string
+	size       int64
+	nextRotate time.Time
+	closed     bool
+
+	// bgMu serializes background compression and removal of old segments
+	bgMu sync.Mutex
+	bgWg sync.WaitGroup
+}
+
+// NewRotatingFile creates a RotatingFile with a given path and options, and opens its current segment. If the
+// newest existing segment was created in the current rotation interval and is smaller than MaxSize, it is
+// appended to; otherwise, a new segment is created.
+func NewRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path: path,
+		opts: opts,
+		now:  time.Now,
+	}
+	err := f.openExisting()
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Name returns the path of the current segment
+func (f *RotatingFile) Name() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.name
+}
+
+// Write appends p to the current segment, first rotating to a new segment if required
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	now := f.now()
+	if (!f.nextRotate.IsZero() && !now.Before(f.nextRotate)) ||
+		(f.opts.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.opts.MaxSize) {
+		err := f.rotate(now)
+		if err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate closes the current segment and opens a new one
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return os.ErrClosed
+	}
+	return f.rotate(f.now())
+}
+
+// Close closes the current segment, and waits for any background compression and removal of old
+// segments to complete
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if !f.closed {
+		f.closed = true
+		err = f.file.Close()
+	}
+	f.mu.Unlock()
+	f.bgWg.Wait()
+	return err
+}
+
+// rotate closes the current segment, opens a new one, and starts background processing of the rotated
+// segment. f.mu must be held.
+func (f *RotatingFile) rotate(now time.Time) error {
+	oldName := f.name
+	err := f.openNew(now)
+	if err != nil {
+		return err
+	}
+	f.bgWg.Add(1)
+	go func() {
+		defer f.bgWg.Done()
+		f.postRotate(oldName)
+	}()
+	return nil
+}
+
+// openExisting opens the newest existing segment if it can be appended to, or a new segment otherwise
+func (f *RotatingFile) openExisting() error {
+	now := f.now()
+	segments, err := f.segments()
+	if err != nil {
+		return err
+	}
+	for i := len(segments) - 1; i >= 0; i-- {
+		seg := segments[i]
+		if seg.compressed {
+			continue
+		}
+		fi, err := os.Stat(seg.path)
+		if err != nil {
+			break
+		}
+		if (f.opts.MaxSize > 0 && fi.Size() >= f.opts.MaxSize) ||
+			(f.opts.Interval != RotateNever && !f.intervalStart(seg.created).Equal(f.intervalStart(now))) {
+			break
+		}
+		file, err := os.OpenFile(seg.path, os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			break
+		}
+		f.setCurrent(file, seg.path, fi.Size(), now)
+		return f.updateSymlink()
+	}
+	return f.openNew(now)
+}
+
+// openNew creates a new segment and makes it the current segment, closing the previous one. f.mu must be
+// held, unless f is being constructed.
+func (f *RotatingFile) openNew(now time.Time) error {
+	dir, stem, ext := f.splitPath()
+	ts := f.localize(now).Format(segmentTimeLayout)
+	var file *os.File
+	var name string
+	for seq := 0; ; seq++ {
+		name = filepath.Join(dir, stem+"-"+ts+ext)
+		if seq > 0 {
+			name = filepath.Join(dir, stem+"-"+ts+"-"+strconv.Itoa(seq)+ext)
+		}
+		if _, err := os.Lstat(name + ".gz"); err == nil {
+			continue
+		}
+		var err error
+		file, err = os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			// A rotated segment with this name may have been compressed and removed since the check above
+			if _, err := os.Lstat(name + ".gz"); err == nil {
+				file.Close()
+				os.Remove(name)
+				continue
+			}
+			break
+		}
+		if !os.IsExist(err) {
+			return err
+		}
+	}
+	if f.file != nil {
+		f.file.Close()
+	}
+	f.setCurrent(file, name, 0, now)
+	return f.updateSymlink()
+}
+
+// setCurrent makes an open file the current segment
+func (f *RotatingFile) setCurrent(file *os.File, name string, size int64, now time.Time) {
+	f.file = file
+	f.name = name
+	f.size = size
+	f.nextRotate = time.Time{}
+	if f.opts.Interval != RotateNever {
+		f.nextRotate = f.nextIntervalStart(now)
+	}
+}
+
+// updateSymlink points the symbolic link at the RotatingFile's path to the current segment, if enabled. The
+// link is replaced atomically.
+func (f *RotatingFile) updateSymlink() error {
+	if !f.opts.Symlink {
+		return nil
+	}
+	tmp := f.path + ".tmp-link"
+	os.Remove(tmp)
+	err := os.Symlink(filepath.Base(f.name), tmp)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// localize converts a time to UTC or local time, according to the options
+func (f *RotatingFile) localize(t time.Time) time.Time {
+	if f.opts.UTC {
+		return t.UTC()
+	}
+	return t.Local()
+}
+
+// intervalStart returns the start of the rotation interval containing t
+func (f *RotatingFile) intervalStart(t time.Time) time.Time {
+	t = f.localize(t)
+	switch f.opts.Interval {
+	case RotateHourly:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case RotateDaily:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+	return time.Time{}
+}
+
+// nextIntervalStart returns the start of the rotation interval following the one containing t
+func (f *RotatingFile) nextIntervalStart(t time.Time) time.Time {
+	start := f.intervalStart(t)
+	switch f.opts.Interval {
+	case RotateHourly:
+		return start.Add(time.Hour)
+	case RotateDaily:
+		return start.AddDate(0, 0, 1)
+	}
+	return time.Time{}
+}
+
+// splitPath splits the RotatingFile's path into a directory, a file name stem, and an extension
+func (f *RotatingFile) splitPath() (dir string, stem string, ext string) {
+	dir, base := filepath.Split(f.path)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext), ext
+}
+
+// segment describes an existing segment file
+type segment struct {
+	path       string
+	created    time.Time
+	seq        int
+	compressed bool
+}
+
+// segments returns the existing segments, oldest first
+func (f *RotatingFile) segments() ([]segment, error) {
+	dir, stem, ext := f.splitPath()
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	loc := time.Local
+	if f.opts.UTC {
+		loc = time.UTC
+	}
+	var result []segment
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || !strings.HasPrefix(name, stem+"-") {
+			continue
+		}
+		rest := strings.TrimPrefix(name, stem+"-")
+		seg := segment{path: filepath.Join(dir, name)}
+		if strings.HasSuffix(rest, ext+".gz") {
+			seg.compressed = true
+			rest = strings.TrimSuffix(rest, ext+".gz")
+		} else if strings.HasSuffix(rest, ext) {
+			rest = strings.TrimSuffix(rest, ext)
+		} else {
+			continue
+		}
+		ts, seqStr, hasSeq := strings.Cut(rest, "-")
+		created, err := time.ParseInLocation(segmentTimeLayout, ts, loc)
+		if err != nil {
+			continue
+		}
+		seg.created = created
+		if hasSeq {
+			seg.seq, err = strconv.Atoi(seqStr)
+			if err != nil {
+				continue
+			}
+		}
+		result = append(result, seg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].created.Equal(result[j].created) {
+			return result[i].created.Before(result[j].created)
+		}
+		return result[i].seq < result[j].seq
+	})
+	return result, nil
+}
+
+// postRotate compresses a rotated segment if enabled, then removes old segments
+func (f *RotatingFile) postRotate(name string) {
+	f.bgMu.Lock()
+	defer f.bgMu.Unlock()
+	if f.opts.Compress {
+		err := compressFile(name)
+		if err != nil {
+			err = fmt.Errorf("unable to compress %s: %w", name, err)
+			if f.onError != nil {
+				f.onError(err)
+			} else {
+				fmt.Fprintf(os.Stderr, "logger: %s\n", err)
+			}
+		}
+	}
+	f.removeOld()
+}
+
+// removeOld removes rotated segments beyond MaxBackups or older than MaxAge
+func (f *RotatingFile) removeOld() {
+	if f.opts.MaxBackups <= 0 && f.opts.MaxAge <= 0 {
+		return
+	}
+	segments, err := f.segments()
+	if err != nil {
+		return
+	}
+	current := f.Name()
+	var backups []segment
+	for _, seg := range segments {
+		if seg.path != current {
+			backups = append(backups, seg)
+		}
+	}
+	cutoff := f.now().Add(-f.opts.MaxAge)
+	for i, seg := range backups {
+		remove := f.opts.MaxBackups > 0 && i < len(backups)-f.opts.MaxBackups
+		if !remove && f.opts.MaxAge > 0 {
+			fi, err := os.Stat(seg.path)
+			remove = err == nil && fi.ModTime().Before(cutoff)
+		}
+		if remove {
+			os.Remove(seg.path)
+		}
+	}
+}
+
+// compressFile compresses a file with gzip, writing name+".gz" and removing the original
+func compressFile(name string) error {
+	in, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(name+".gz", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, in)
+	if err == nil {
+		err = zw.Close()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(name + ".gz")
+		return err
+	}
+	return os.Remove(name)
+}
+
+// WithRotatingFile causes log output to be written to a RotatingFile with a given path and options. The file is
+// opened by NewWithConfig. This setting replaces any prior effect of WithWriter(), WithLogger(), WithHandler(),
+// WithLogFile() or WithReopenableLogFile().
+func WithRotatingFile(path string, opts RotateOptions) ConfigOption {
+	return func(cfg *Config) {
+		cfg.logFile = path
+		cfg.rotateOpts = &opts
+		cfg.reopenable = false
+		cfg.logWriter = nil
+		cfg.parentLogger = nil
+		cfg.handler = nil
+	}
+}
@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler is a slog.Handler that routes slog Records into a Logger, so that output from libraries
+// that log through log/slog lands in the same stream, with the same prefixes, as output from the Logger.
+// slog levels are mapped onto LogLevels with LogLevelFromSlog, WithGroup forks a new prefix segment with
+// ForkLogStr, and attributes become structured fields.
+type SlogHandler struct {
+	lg Logger
+}
+
+// NewSlogHandler creates a slog.Handler that routes slog Records into a Logger
+func NewSlogHandler(lg Logger) *SlogHandler {
+	h := &SlogHandler{
+		lg: lg,
+	}
+	return h
+}
+
+// NewSlogLogger creates a *slog.Logger that routes its output into a Logger
+func NewSlogLogger(lg Logger) *slog.Logger {
+	return slog.New(NewSlogHandler(lg))
+}
+
+// Slog returns a *slog.Logger that routes its output into this logger
+func (l *BasicLogger) Slog() *slog.Logger {
+	return NewSlogLogger(l)
+}
+
+// SlogPrefixKey is the attribute key under which the prefix chain of a Record is passed to a slog.Handler
+// by the Handler returned from NewHandlerFromSlog
+const SlogPrefixKey = "prefix"
+
+// slogRecordHandler is a Handler that converts Records to slog Records and passes them to a slog.Handler
+type slogRecordHandler struct {
+	h slog.Handler
+}
+
+// NewHandlerFromSlog creates a Handler that converts each Record into a slog Record and passes it to a
+// slog.Handler. The level is converted with LogLevelToSlog, the prefix chain is passed as a string attribute
+// with key SlogPrefixKey, and the fields are passed as attributes. Records at levels that the slog.Handler
+// does not enable are discarded.
+func NewHandlerFromSlog(h slog.Handler) Handler {
+	return &slogRecordHandler{h: h}
+}
+
+// NewFromSlogHandler creates a new Logger that writes to a slog.Handler, with an optional prefix and a
+// loglevel. See NewHandlerFromSlog.
+func NewFromSlogHandler(h slog.Handler, prefix string, logLevel LogLevel) Logger {
+	return NewWithHandler(NewHandlerFromSlog(h), prefix, logLevel)
+}
+
+// Handle converts a Record into a slog Record and passes it to the slog.Handler
+func (h *slogRecordHandler) Handle(r *Record) error {
+	ctx := context.Background()
+	level := LogLevelToSlog(r.Level)
+	if !h.h.Enabled(ctx, level) {
+		return nil
+	}
+	sr := slog.NewRecord(r.Time, level, r.Message, r.PC)
+	if len(r.PrefixPath) > 0 {
+		sr.AddAttrs(slog.String(SlogPrefixKey, r.Prefix()))
+	}
+	for _, f := range r.Fields {
+		sr.AddAttrs(slog.Any(f.Key, f.Value))
+	}
+	return h.h.Handle(ctx, sr)
+}
+
+// LogLevelFromSlog converts a slog.Level to a LogLevel. Levels at or above slog.LevelError map to
+// LogLevelError (never to LogLevelPanic or LogLevelFatal), and levels below slog.LevelDebug map to LogLevelTrace.
+func LogLevelFromSlog(level slog.Level) LogLevel {
+	switch {
+	case level >= slog.LevelError:
+		return LogLevelError
+	case level >= slog.LevelWarn:
+		return LogLevelWarning
+	case level >= slog.LevelInfo:
+		return LogLevelInfo
+	case level >= slog.LevelDebug:
+		return LogLevelDebug
+	default:
+		return LogLevelTrace
+	}
+}
+
+// LogLevelToSlog converts a LogLevel to a slog.Level. LogLevelTrace maps to slog.LevelDebug-4, LogLevelFatal
+// to slog.LevelError+4 and LogLevelPanic to slog.LevelError+8. LogLevelUnknown maps to slog.LevelInfo.
+func LogLevelToSlog(logLevel LogLevel) slog.Level {
+	switch logLevel {
+	case LogLevelPanic:
+		return slog.LevelError + 8
+	case LogLevelFatal:
+		return slog.LevelError + 4
+	case LogLevelError:
+		return slog.LevelError
+	case LogLevelWarning:
+		return slog.LevelWarn
+	case LogLevelDebug:
+		return slog.LevelDebug
+	case LogLevelTrace:
+		return slog.LevelDebug - 4
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Enabled reports whether the Logger's level enables records at the given slog level
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return LogLevelFromSlog(level) <= h.lg.GetLogLevel()
+}
+
+// Handle logs a slog Record to the Logger
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	logLevel := LogLevelFromSlog(r.Level)
+	var fields []Field
+	if r.NumAttrs() > 0 {
+		fields = make([]Field, 0, r.NumAttrs())
+		r.Attrs(func(a slog.Attr) bool {
+			fields = appendSlogAttr(fields, "", a)
+			return true
+		})
+	}
+
+	lr := &Record{
+		Time:    r.Time,
+		Level:   logLevel,
+		Message: r.Message,
+		PC:      r.PC,
+		Fields:  fields,
+	}
+	return LogRecord(h.lg, lr)
+}
+
+// WithAttrs returns a new SlogHandler whose Logger has the attributes added as structured fields
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	var fields []Field
+	for _, a := range attrs {
+		fields = appendSlogAttr(fields, "", a)
+	}
+	return NewSlogHandler(h.lg.ForkWith(fieldsToKeysAndValues(fields)...))
+}
+
+// WithGroup returns a new SlogHandler whose Logger has the group name added as a prefix segment
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return NewSlogHandler(h.lg.ForkLogStr(name))
+}
+
+// appendSlogAttr appends the fields for a slog.Attr to fields. Group attributes are flattened, with
+// keys joined by ".".
+func appendSlogAttr(fields []Field, keyPrefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := keyPrefix
+		if a.Key != "" {
+			groupPrefix = keyPrefix + a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendSlogAttr(fields, groupPrefix, ga)
+		}
+		return fields
+	}
+	return append(fields, Field{Key: keyPrefix + a.Key, Value: a.Value.Any()})
+}
+
+// fieldsToKeysAndValues converts Fields to a list of arguments suitable for ForkWith or Logw
+func fieldsToKeysAndValues(fields []Field) []interface{} {
+	result := make([]interface{}, len(fields))
+	for i, f := range fields {
+		result[i] = f
+	}
+	return result
+}
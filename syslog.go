@@ -0,0 +1,462 @@
+package logger
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SyslogFormat selects the message format used by a SyslogSink
+type SyslogFormat int
+
+const (
+	// SyslogRFC5424 formats messages as described in RFC 5424. The record's prefix and fields are passed as
+	// structured data.
+	SyslogRFC5424 SyslogFormat = iota
+
+	// SyslogRFC3164 formats messages in the traditional BSD syslog format described in RFC 3164. The record's
+	// prefix and fields are included in the message text.
+	SyslogRFC3164 SyslogFormat = iota
+)
+
+// SyslogFraming selects how a SyslogSink delimits messages on a connection
+type SyslogFraming int
+
+const (
+	// SyslogFramingAuto uses SyslogFramingOctetCounting for TCP and TLS connections, SyslogFramingNewline for
+	// unix stream sockets, and no framing for datagram sockets
+	SyslogFramingAuto SyslogFraming = iota
+
+	// SyslogFramingOctetCounting prefixes each message with its length in bytes and a space (RFC 6587 section 3.4.1)
+	SyslogFramingOctetCounting SyslogFraming = iota
+
+	// SyslogFramingNewline terminates each message with a newline (RFC 6587 section 3.4.2). Messages that contain
+	// newlines are split by the receiver.
+	SyslogFramingNewline SyslogFraming = iota
+)
+
+// SyslogFacility is a syslog facility code (RFC 5424 section 6.2.1)
+type SyslogFacility int
+
+// Syslog facility codes
+const (
+	SyslogFacilityKern     SyslogFacility = 0
+	SyslogFacilityUser     SyslogFacility = 1
+	SyslogFacilityMail     SyslogFacility = 2
+	SyslogFacilityDaemon   SyslogFacility = 3
+	SyslogFacilityAuth     SyslogFacility = 4
+	SyslogFacilitySyslog   SyslogFacility = 5
+	SyslogFacilityLPR      SyslogFacility = 6
+	SyslogFacilityNews     SyslogFacility = 7
+	SyslogFacilityUUCP     SyslogFacility = 8
+	SyslogFacilityCron     SyslogFacility = 9
+	SyslogFacilityAuthPriv SyslogFacility = 10
+	SyslogFacilityFTP      SyslogFacility = 11
+	SyslogFacilityLocal0   SyslogFacility = 16
+	SyslogFacilityLocal1   SyslogFacility = 17
+	SyslogFacilityLocal2   SyslogFacility = 18
+	SyslogFacilityLocal3   SyslogFacility = 19
+	SyslogFacilityLocal4   SyslogFacility = 20
+	SyslogFacilityLocal5   SyslogFacility = 21
+	SyslogFacilityLocal6   SyslogFacility = 22
+	SyslogFacilityLocal7   SyslogFacility = 23
+)
+
+// SyslogStructuredDataID is the SD-ID of the RFC 5424 structured data element in which a SyslogSink passes
+// the prefix and fields of a record. 32473 is the private enterprise number reserved for documentation.
+const SyslogStructuredDataID = "logger@32473"
+
+// defaultSyslogDialTimeout is the time limit for connecting to a syslog server if none is configured
+const defaultSyslogDialTimeout = 5 * time.Second
+
+// syslogReconnectDelay is the minimum time between attempts to reconnect to a syslog server
+const syslogReconnectDelay = time.Second
+
+// syslogLocalPaths are the unix socket paths tried by a SyslogSink connecting to the local syslog daemon
+var syslogLocalPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogOptions configures a SyslogSink
+type SyslogOptions struct {
+	// Network is the network used to reach the syslog server: "udp", "tcp", "unix" or "unixgram" (or a variant
+	// such as "udp4" accepted by net.Dial). If empty, the local syslog daemon is reached through a unix socket,
+	// either datagram or stream, at Addr, or if Addr is empty, at the first of the usual paths that exists.
+	Network string
+
+	// Addr is the address of the syslog server, as accepted by net.Dial
+	Addr string
+
+	// TLSConfig, if not nil, causes a "tcp" connection to be secured with TLS (RFC 5425)
+	TLSConfig *tls.Config
+
+	// Format selects RFC 5424 (the default) or RFC 3164 messages
+	Format SyslogFormat
+
+	// Framing selects how messages are delimited on a connection. The default is SyslogFramingAuto.
+	Framing SyslogFraming
+
+	// Facility is the facility code of each message. Since only the kernel may log with SyslogFacilityKern, the
+	// zero value selects SyslogFacilityUser.
+	Facility SyslogFacility
+
+	// AppName is the APP-NAME (or, for RFC 3164, the TAG) of each message. If empty, the base name of the
+	// program is used.
+	AppName string
+
+	// Hostname is the HOSTNAME of each message. If empty, os.Hostname() is used.
+	Hostname string
+
+	// DialTimeout limits the time taken to connect to the syslog server. If zero, 5 seconds is used.
+	DialTimeout time.Duration
+}
+
+// SyslogSink is a Handler that sends Records to a syslog server. LogLevels are mapped to syslog severities
+// as by LevelTagSyslog: panic to emerg, fatal to crit, error to err, warning to warning, info to info, and
+// debug and trace to debug.
+//
+// SyslogSink connects when it is created. If a connection fails, it reconnects when the next record is
+// handled. Since records are handled on the logging goroutine, a failed attempt to reconnect is not repeated
+// for a second; records handled in the meantime fail with the same error. Before each write to a stream
+// connection, SyslogSink checks whether the server has closed the connection, so that records are not lost
+// when the server is restarted between records.
+type SyslogSink struct {
+	network     string
+	addr        string
+	tlsConfig   *tls.Config
+	format      SyslogFormat
+	framing     SyslogFraming
+	facility    SyslogFacility
+	appName     string
+	hostname    string
+	procID      string
+	dialTimeout time.Duration
+
+	mu          sync.Mutex
+	conn        net.Conn
+	connFraming SyslogFraming
+	stream      bool
+	closed      bool
+	dialErr     error
+	retryAt     time.Time
+}
+
+// NewSyslogSink creates a SyslogSink and connects it to a syslog server
+func NewSyslogSink(opts SyslogOptions) (*SyslogSink, error) {
+	s := &SyslogSink{
+		network:     opts.Network,
+		addr:        opts.Addr,
+		tlsConfig:   opts.TLSConfig,
+		format:      opts.Format,
+		framing:     opts.Framing,
+		facility:    opts.Facility,
+		appName:     opts.AppName,
+		hostname:    opts.Hostname,
+		procID:      strconv.Itoa(os.Getpid()),
+		dialTimeout: opts.DialTimeout,
+	}
+	if s.dialTimeout <= 0 {
+		s.dialTimeout = defaultSyslogDialTimeout
+	}
+	if s.facility == SyslogFacilityKern {
+		s.facility = SyslogFacilityUser
+	}
+	if s.appName == "" {
+		s.appName = filepath.Base(os.Args[0])
+	}
+	if s.hostname == "" {
+		s.hostname, _ = os.Hostname()
+	}
+
+	err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewSyslogLogger creates a new Logger that sends its output to a syslog server, with an optional prefix and
+// a loglevel. See NewSyslogSink.
+func NewSyslogLogger(opts SyslogOptions, prefix string, logLevel LogLevel) (Logger, error) {
+	s, err := NewSyslogSink(opts)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithHandler(s, prefix, logLevel), nil
+}
+
+// Handle formats a Record as a syslog message and sends it to the syslog server, reconnecting if necessary
+func (s *SyslogSink) Handle(r *Record) error {
+	msg := s.appendMessage(make([]byte, 0, 256), r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return os.ErrClosed
+	}
+	if s.conn != nil && s.stream && !connAlive(s.conn) {
+		s.closeConn()
+	}
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			err = s.reconnect()
+			if err != nil {
+				return err
+			}
+		}
+		_, err = s.conn.Write(s.frame(msg))
+		if err == nil {
+			return nil
+		}
+		s.closeConn()
+	}
+	return err
+}
+
+// Close closes the connection to the syslog server. Subsequent records fail with os.ErrClosed.
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// closeConn closes the current connection, ignoring errors. s.mu must be held.
+func (s *SyslogSink) closeConn() {
+	s.conn.Close()
+	s.conn = nil
+}
+
+// reconnect connects to the syslog server, unless an attempt failed less than syslogReconnectDelay ago, in which
+// case that attempt's error is returned. s.mu must be held.
+func (s *SyslogSink) reconnect() error {
+	if s.dialErr != nil && time.Now().Before(s.retryAt) {
+		return s.dialErr
+	}
+	s.dialErr = s.connect()
+	if s.dialErr != nil {
+		s.retryAt = time.Now().Add(syslogReconnectDelay)
+	}
+	return s.dialErr
+}
+
+// connect connects to the syslog server. s.mu must be held, or s must not yet be shared.
+func (s *SyslogSink) connect() error {
+	var conn net.Conn
+	var network string
+	var err error
+	if s.network == "" {
+		conn, network, err = s.dialLocal()
+	} else {
+		network = s.network
+		conn, err = s.dial(network, s.addr)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.conn = conn
+	s.stream = isStreamNetwork(network)
+	s.connFraming = s.framing
+	if s.connFraming == SyslogFramingAuto && s.stream {
+		s.connFraming = SyslogFramingOctetCounting
+		if network == "unix" {
+			s.connFraming = SyslogFramingNewline
+		}
+	}
+	return nil
+}
+
+// dial connects to addr on a network, with TLS if configured
+func (s *SyslogSink) dial(network string, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: s.dialTimeout}
+	if s.tlsConfig != nil && isStreamNetwork(network) && network != "unix" {
+		return tls.DialWithDialer(dialer, network, addr, s.tlsConfig)
+	}
+	return dialer.Dial(network, addr)
+}
+
+// dialLocal connects to the local syslog daemon through a datagram or stream unix socket, and returns the
+// connection and its network
+func (s *SyslogSink) dialLocal() (net.Conn, string, error) {
+	paths := syslogLocalPaths
+	if s.addr != "" {
+		paths = []string{s.addr}
+	}
+	var err error
+	for _, path := range paths {
+		for _, network := range []string{"unixgram", "unix"} {
+			var conn net.Conn
+			conn, err = s.dial(network, path)
+			if err == nil {
+				return conn, network, nil
+			}
+		}
+	}
+	return nil, "", err
+}
+
+// isStreamNetwork returns true if a network name accepted by net.Dial refers to a stream protocol
+func isStreamNetwork(network string) bool {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		return true
+	}
+	return false
+}
+
+// connAlive returns false if the peer of a stream connection has closed it. Syslog servers do not send data
+// to their clients, so nothing is normally pending on the connection. If something is, it is read through
+// the connection, so that TLS records such as session tickets are consumed; anything other than a timeout
+// indicates that the connection is closed (or the server is misbehaving).
+func connAlive(conn net.Conn) bool {
+	raw := conn
+	tc, ok := conn.(*tls.Conn)
+	if ok {
+		raw = tc.NetConn()
+	}
+	if !readPending(raw) {
+		return true
+	}
+	err := conn.SetReadDeadline(time.Now().Add(time.Millisecond))
+	if err != nil {
+		return false
+	}
+	var b [1]byte
+	_, err = conn.Read(b[:])
+	conn.SetReadDeadline(time.Time{})
+	return errors.Is(err, os.ErrDeadlineExceeded)
+}
+
+// frame returns a message framed for the current connection. s.mu must be held.
+func (s *SyslogSink) frame(msg []byte) []byte {
+	switch s.connFraming {
+	case SyslogFramingOctetCounting:
+		b := make([]byte, 0, len(msg)+8)
+		b = strconv.AppendInt(b, int64(len(msg)), 10)
+		b = append(b, ' ')
+		return append(b, msg...)
+	case SyslogFramingNewline:
+		return append(msg, '\n')
+	}
+	return msg
+}
+
+// appendMessage appends the unframed syslog message for a Record to b
+func (s *SyslogSink) appendMessage(b []byte, r *Record) []byte {
+	b = append(b, '<')
+	b = strconv.AppendInt(b, int64(int(s.facility)*8+syslogSeverity(r.Level)), 10)
+	b = append(b, '>')
+	if s.format == SyslogRFC3164 {
+		t := r.Time
+		if t.IsZero() {
+			t = time.Now()
+		}
+		b = t.AppendFormat(b, time.Stamp)
+		b = append(b, ' ')
+		b = appendSyslogHeaderField(b, s.hostname, 255)
+		b = append(b, ' ')
+		b = appendSyslogHeaderField(b, s.appName, 32)
+		b = append(b, '[')
+		b = append(b, s.procID...)
+		b = append(b, "]: "...)
+		return r.AppendText(b)
+	}
+
+	b = append(b, "1 "...)
+	if r.Time.IsZero() {
+		b = append(b, '-')
+	} else {
+		b = r.Time.AppendFormat(b, "2006-01-02T15:04:05.000000Z07:00")
+	}
+	b = append(b, ' ')
+	b = appendSyslogHeaderField(b, s.hostname, 255)
+	b = append(b, ' ')
+	b = appendSyslogHeaderField(b, s.appName, 48)
+	b = append(b, ' ')
+	b = appendSyslogHeaderField(b, s.procID, 128)
+	b = append(b, " - "...)
+	b = appendSyslogStructuredData(b, r)
+	if r.Message != "" {
+		b = append(b, ' ')
+		b = append(b, r.Message...)
+	}
+	return b
+}
+
+// appendSyslogHeaderField appends an RFC 5424 header field to b, truncated to maxLen bytes, with characters other
+// than printable ASCII replaced with '_'. An empty field is appended as "-".
+func appendSyslogHeaderField(b []byte, s string, maxLen int) []byte {
+	if s == "" {
+		return append(b, '-')
+	}
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c > '~' {
+			c = '_'
+		}
+		b = append(b, c)
+	}
+	return b
+}
+
+// appendSyslogStructuredData appends the RFC 5424 STRUCTURED-DATA for a record to b: an SD-ELEMENT with
+// SD-ID SyslogStructuredDataID and an SD-PARAM for the prefix (if any) and each field, or "-" if there
+// are none
+func appendSyslogStructuredData(b []byte, r *Record) []byte {
+	if len(r.PrefixPath) == 0 && len(r.Fields) == 0 {
+		return append(b, '-')
+	}
+	b = append(b, '[')
+	b = append(b, SyslogStructuredDataID...)
+	if len(r.PrefixPath) > 0 {
+		b = appendSyslogParam(b, "prefix", r.Prefix())
+	}
+	for _, f := range r.Fields {
+		b = appendSyslogParam(b, f.Key, fieldValueString(f.Value))
+	}
+	return append(b, ']')
+}
+
+// appendSyslogParam appends an SD-PARAM to b. The name is truncated to 32 bytes, with characters that are not
+// valid in a PARAM-NAME replaced with '_'; '"', '\' and ']' in the value are escaped with '\'.
+func appendSyslogParam(b []byte, name string, value string) []byte {
+	b = append(b, ' ')
+	if name == "" {
+		name = "_"
+	}
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c <= ' ' || c > '~' || c == '=' || c == ']' || c == '"' {
+			c = '_'
+		}
+		b = append(b, c)
+	}
+	b = append(b, "=\""...)
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c == '"' || c == '\\' || c == ']' {
+			b = append(b, '\\')
+		}
+		b = append(b, c)
+	}
+	return append(b, '"')
+}
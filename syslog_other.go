@@ -0,0 +1,13 @@
+//go:build !unix
+
+package logger
+
+import (
+	"net"
+)
+
+// readPending returns true if data, an end of file or an error is pending on a connection. It is not
+// implemented on this platform, and always returns false.
+func readPending(conn net.Conn) bool {
+	return false
+}
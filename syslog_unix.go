@@ -0,0 +1,29 @@
+//go:build unix
+
+package logger
+
+import (
+	"net"
+	"syscall"
+)
+
+// readPending returns true if data, an end of file or an error is pending on a connection, without
+// consuming it
+func readPending(conn net.Conn) bool {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return false
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return false
+	}
+	pending := false
+	err = raw.Control(func(fd uintptr) {
+		var b [1]byte
+		// The socket is non-blocking, so EAGAIN indicates that nothing is pending
+		_, _, err := syscall.Recvfrom(int(fd), b[:], syscall.MSG_PEEK)
+		pending = err != syscall.EAGAIN && err != syscall.EWOULDBLOCK
+	})
+	return err == nil && pending
+}
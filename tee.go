@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// TeeDestination is one of the destinations of a TeeHandler
+type TeeDestination struct {
+	// Handler receives the records passed to this destination; e.g., a WriterHandler with the destination's
+	// Formatter, or a SyslogSink. If Handler is nil, records are written to Writer in Format. Either Handler
+	// or Writer must be set.
+	Handler Handler
+
+	// Writer receives the records passed to this destination, rendered in Format, if Handler is nil
+	Writer io.Writer
+
+	// Format is the output format used for Writer. The timestamp, caller and level tag options are those of
+	// the Config of the logger that uses the TeeHandler (see WithLogFlags, WithFormatOptions, WithLevelTag
+	// and WithColor), or the defaults if the TeeHandler is used with NewWithHandler.
+	Format Format
+
+	// Level is the least severe level passed to this destination. Records without a level, such as the output
+	// of Print, are always passed. If Level is LogLevelUnknown, all records are passed.
+	Level LogLevel
+}
+
+// TeeHandler is a Handler that passes each Record to several destinations, each with its own level and
+// Handler or output format. For example, to write JSON at debug level to a file, console output at warning
+// level to stderr, and errors to syslog:
+//
+//	lg, err := logger.New(logger.WithTee(
+//		logger.TeeDestination{Writer: f, Format: logger.FormatJSON, Level: logger.LogLevelDebug},
+//		logger.TeeDestination{Writer: os.Stderr, Format: logger.FormatConsole, Level: logger.LogLevelWarning},
+//		logger.TeeDestination{Handler: syslogSink, Level: logger.LogLevelError},
+//	), logger.WithLogLevel(logger.LogLevelDebug), logger.WithLUTC())
+//
+// Failures are isolated: a record is passed to every destination even if an earlier destination returns an
+// error or panics. The errors (including recovered panics) are returned together.
+type TeeHandler struct {
+	dests []TeeDestination
+	// handlers are the Handlers of the destinations, including WriterHandlers for destinations with a Writer
+	handlers []Handler
+	// formatted is true if any destination has a Writer
+	formatted bool
+	// err is the error for the first invalid destination, if any
+	err error
+}
+
+// NewTeeHandler creates a TeeHandler that passes each Record to the given destinations, in order. An error is
+// returned if a destination has neither a Handler nor a Writer, or if its Format is invalid; the TeeHandler is
+// still returned, and returns the error for every record passed to that destination.
+func NewTeeHandler(dests ...TeeDestination) (*TeeHandler, error) {
+	return newTeeHandler(NewConfig(), dests)
+}
+
+// newTeeHandler creates a TeeHandler whose destinations with a Writer use the format options of a Config. If a
+// destination is invalid, an error is returned, and the TeeHandler returns it for every record passed to that
+// destination.
+func newTeeHandler(cfg *Config, dests []TeeDestination) (*TeeHandler, error) {
+	h := &TeeHandler{
+		dests:    append([]TeeDestination(nil), dests...),
+		handlers: make([]Handler, len(dests)),
+	}
+	for i, d := range h.dests {
+		if d.Handler != nil {
+			h.handlers[i] = d.Handler
+			continue
+		}
+		if d.Writer == nil {
+			h.setInvalid(i, fmt.Errorf("Tee destination %d has neither a Handler nor a Writer", i))
+			continue
+		}
+		h.formatted = true
+		formatter, err := cfg.formatterFor(d.Format, d.Writer)
+		if err != nil {
+			h.setInvalid(i, err)
+			continue
+		}
+		h.handlers[i] = NewWriterHandler(d.Writer, formatter)
+	}
+	return h, h.err
+}
+
+// setInvalid causes the destination with index i to return err for every record, and records err as the
+// construction error if it is the first
+func (h *TeeHandler) setInvalid(i int, err error) {
+	h.handlers[i] = HandlerFunc(func(r *Record) error {
+		return err
+	})
+	if h.err == nil {
+		h.err = err
+	}
+}
+
+// withConfig returns a TeeHandler whose destinations with a Writer use the format options of a Config, or h
+// itself if there are none. An error is returned if a destination is invalid.
+func (h *TeeHandler) withConfig(cfg *Config) (*TeeHandler, error) {
+	if !h.formatted {
+		return h, h.err
+	}
+	return newTeeHandler(cfg, h.dests)
+}
+
+// Handle passes a Record to each destination whose level enables it. The returned error combines the
+// errors returned by the destinations, if any.
+func (h *TeeHandler) Handle(r *Record) error {
+	var errs []error
+	for i := range h.dests {
+		d := &h.dests[i]
+		if r.Level != LogLevelUnknown && d.Level != LogLevelUnknown && r.Level > d.Level {
+			continue
+		}
+		// Each destination receives its own copy, two frames deeper (in handleOne)
+		dr := *r
+		dr.CallDepth += 2
+		err := handleOne(h.handlers[i], &dr)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// handleOne passes a Record to a Handler, converting a panic into an error
+func handleOne(handler Handler, r *Record) (err error) {
+	defer func() {
+		x := recover()
+		if x != nil {
+			err = fmt.Errorf("Handler panicked: %v", x)
+		}
+	}()
+	return handler.Handle(r)
+}
+
+// GetLogLevel returns the most verbose level of the destinations, taking into account the levels of their
+// Handlers if they implement GetLogLeveler, so that a Logger created with NewWithHandler does not format
+// records that no destination would accept
+func (h *TeeHandler) GetLogLevel() LogLevel {
+	result := LogLevelUnknown
+	for i, d := range h.dests {
+		logLevel := d.Level
+		if logLevel == LogLevelUnknown {
+			logLevel = LogLevelTrace
+		}
+		gll, ok := h.handlers[i].(GetLogLeveler)
+		if ok && gll.GetLogLevel() < logLevel {
+			logLevel = gll.GetLogLevel()
+		}
+		if logLevel > result {
+			result = logLevel
+		}
+	}
+	return result
+}
+
+// WithTee causes log output to be passed to several destinations, each with its own level and Handler or
+// output format, using a TeeHandler. Destinations with a Writer use the new logger's format options. An
+// invalid destination (see NewTeeHandler) is returned as an error by NewWithConfig. This setting replaces any
+// prior effect of WithWriter(), WithLogger(), WithHandler(), WithLogFile(), WithRotatingFile() or
+// WithReopenableLogFile().
+func WithTee(dests ...TeeDestination) ConfigOption {
+	return func(cfg *Config) {
+		h, err := NewTeeHandler(dests...)
+		if err != nil {
+			cfg.addError(err)
+		}
+		WithHandler(h)(cfg)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package logger
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// isTerminalFile returns true if f refers to a terminal
+func isTerminalFile(f *os.File) bool {
+	// SyscallConn is used rather than Fd, which would put the file into blocking mode
+	raw, err := f.SyscallConn()
+	if err != nil {
+		return false
+	}
+	var termErr error
+	err = raw.Control(func(fd uintptr) {
+		_, termErr = unix.IoctlGetTermios(int(fd), unix.TIOCGETA)
+	})
+	return err == nil && termErr == nil
+}
@@ -0,0 +1,18 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd && !windows
+
+package logger
+
+import (
+	"os"
+)
+
+// isTerminalFile returns true if f refers to a character device. On this platform there is no
+// reliable terminal check, so other character devices such as /dev/null are also reported as
+// terminals; the NO_COLOR environment variable or WithColor(ColorNever) can be used to disable color.
+func isTerminalFile(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,34 @@
+package logger
+
+// TextFormatter is a Formatter that renders each Record as a traditional text line in the style of
+// log.Logger: an optional timestamp and caller, followed by the record's text (see Record.Text) with
+// an optional level tag.
+type TextFormatter struct {
+	FormatOptions
+
+	// LevelTag selects how the record's level is rendered
+	LevelTag LevelTag
+}
+
+// NewTextFormatter creates a TextFormatter with the given options and level tag
+func NewTextFormatter(opts FormatOptions, levelTag LevelTag) *TextFormatter {
+	return &TextFormatter{FormatOptions: opts, LevelTag: levelTag}
+}
+
+// Format appends the text rendering of a Record, including a trailing newline, to b
+func (f *TextFormatter) Format(b []byte, r *Record) []byte {
+	if f.TimeLayout != "" {
+		b = f.appendTime(b, r.Time)
+		b = append(b, ' ')
+	}
+	caller := f.caller(r.PC)
+	if caller != "" {
+		b = append(b, caller...)
+		b = append(b, ": "...)
+	}
+	b = f.LevelTag.appendText(b, r)
+	if len(b) == 0 || b[len(b)-1] != '\n' {
+		b = append(b, '\n')
+	}
+	return b
+}
@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// maxLineLength is the length at which a partial line written to a Writer is logged without waiting for the
+// rest of the line
+const maxLineLength = 64 * 1024
+
+// lineWriter is an io.WriteCloser that logs each line written to it
+type lineWriter struct {
+	lg       Logger
+	logLevel LogLevel
+
+	mu     sync.Mutex
+	buf    []byte
+	closed bool
+}
+
+// newLineWriter creates an io.WriteCloser that logs each line written to it to lg at logLevel
+func newLineWriter(lg Logger, logLevel LogLevel) *lineWriter {
+	w := &lineWriter{
+		lg:       lg,
+		logLevel: logLevel,
+	}
+	return w
+}
+
+// Writer returns an io.WriteCloser that splits the bytes written to it into lines, and logs each line
+// at the given level with the logger's prefix and fields. A trailing "\r" is removed from each line, and lines
+// longer than 64 KiB are split.
+// Close logs any final partial line; subsequent writes fail with os.ErrClosed. Lines logged at
+// LogLevelPanic or LogLevelFatal do not exit. The Writer is useful for libraries that only accept an
+// io.Writer or *log.Logger, such as exec.Cmd.Stderr or http.Server.ErrorLog. Lines are attributed to the
+// caller of Write or Close, skipping frames in the standard fmt and log packages. It may be used from multiple
+// goroutines.
+func (l *BasicLogger) Writer(logLevel LogLevel) io.WriteCloser {
+	return newLineWriter(l, logLevel)
+}
+
+// Write logs each complete line in p, and buffers any final partial line
+func (w *lineWriter) Write(p []byte) (int, error) {
+	lines, err := w.splitLines(p)
+	if err != nil {
+		return 0, err
+	}
+	// Lines are logged without holding w.mu, so that a handler that writes back to the Writer (for example,
+	// through the standard logger after RedirectStdLog) does not deadlock
+	if len(lines) > 0 {
+		pc := callerPC()
+		for _, line := range lines {
+			w.logLine(line, pc)
+		}
+	}
+	return len(p), nil
+}
+
+// splitLines adds p to the buffered partial line, and removes and returns the complete lines. A partial
+// line that reaches maxLineLength bytes is returned as a line of its own.
+func (w *lineWriter) splitLines(p []byte) ([]string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil, os.ErrClosed
+	}
+	var lines []string
+	for len(p) > 0 {
+		room := maxLineLength - len(w.buf)
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 && len(p) < room {
+			w.buf = append(w.buf, p...)
+			break
+		}
+		n := i
+		if i < 0 || i > room {
+			n = room
+		}
+		var line string
+		if len(w.buf) > 0 {
+			line = string(append(w.buf, p[:n]...))
+			w.buf = w.buf[:0]
+		} else {
+			line = string(p[:n])
+		}
+		lines = append(lines, line)
+		if n == i {
+			n++
+		}
+		p = p[n:]
+	}
+	return lines, nil
+}
+
+// Close logs any final partial line
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	line := string(w.buf)
+	w.buf = nil
+	w.mu.Unlock()
+	if len(line) > 0 {
+		w.logLine(line, callerPC())
+	}
+	return nil
+}
+
+// logLine logs a single line, attributed to the code at pc
+func (w *lineWriter) logLine(line string, pc uintptr) {
+	r := &Record{
+		Level:   w.logLevel,
+		Message: strings.TrimSuffix(line, "\r"),
+		PC:      pc,
+	}
+	LogRecord(w.lg, r)
+}
+
+// callerPC returns the PC of the caller of Write or Close, skipping frames in the standard fmt and log packages
+func callerPC() uintptr {
+	var pcs [32]uintptr
+	// Skip runtime.Callers, callerPC and Write or Close
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "fmt.") && !strings.HasPrefix(frame.Function, "log.") {
+			return frame.PC
+		}
+		if !more {
+			return 0
+		}
+	}
+}
+
+// RedirectStdLog redirects the output of the standard log package (log.Print, log.Printf, etc.) to a Logger at
+// a given level, so that output from dependencies that use the standard logger is captured. The standard logger's
+// flags and prefix are cleared, since the Logger adds its own. The returned function restores the standard logger's
+// previous output, flags and prefix.
+func RedirectStdLog(lg Logger, logLevel LogLevel) (restore func()) {
+	oldWriter := log.Writer()
+	oldFlags := log.Flags()
+	oldPrefix := log.Prefix()
+
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(newLineWriter(lg, logLevel))
+
+	return func() {
+		log.SetOutput(oldWriter)
+		log.SetFlags(oldFlags)
+		log.SetPrefix(oldPrefix)
+	}
+}
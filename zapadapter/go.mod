@@ -0,0 +1,16 @@
+module github.com/sammck-go/logger/zapadapter
+
+go 1.21
+
+require (
+	github.com/sammck-go/logger v0.0.0-20261017014416-8404c22dd9ce
+	go.uber.org/zap v1.28.0
+)
+
+require (
+	github.com/spf13/pflag v1.0.5 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+)
+
+replace github.com/sammck-go/logger => ../
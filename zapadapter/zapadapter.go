@@ -0,0 +1,202 @@
+/*
+Package zapadapter connects a logger.Logger with go.uber.org/zap in both directions. NewCore and New expose a
+logger.Logger as a zapcore.Core or *zap.Logger, for dependencies that expect zap; NewHandler and NewLogger write
+the output of a logger.Logger to an existing *zap.Logger.
+
+Logger prefix segments correspond to zap logger names: each prefix segment is a name segment, and zap joins
+name segments with ".".
+*/
+package zapadapter
+
+import (
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/sammck-go/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TraceLevel is the zap level used for logger.LogLevelTrace, which has no zap equivalent
+const TraceLevel = zapcore.DebugLevel - 1
+
+// LogLevelFromZap converts a zapcore.Level to a LogLevel. zapcore.DPanicLevel maps to LogLevelError, and
+// levels below zapcore.DebugLevel map to LogLevelTrace.
+func LogLevelFromZap(level zapcore.Level) logger.LogLevel {
+	switch {
+	case level >= zapcore.FatalLevel:
+		return logger.LogLevelFatal
+	case level == zapcore.PanicLevel:
+		return logger.LogLevelPanic
+	case level >= zapcore.ErrorLevel:
+		return logger.LogLevelError
+	case level == zapcore.WarnLevel:
+		return logger.LogLevelWarning
+	case level == zapcore.InfoLevel:
+		return logger.LogLevelInfo
+	case level == zapcore.DebugLevel:
+		return logger.LogLevelDebug
+	default:
+		return logger.LogLevelTrace
+	}
+}
+
+// LogLevelToZap converts a LogLevel to a zapcore.Level. LogLevelTrace maps to TraceLevel, and LogLevelUnknown
+// maps to zapcore.InfoLevel.
+func LogLevelToZap(logLevel logger.LogLevel) zapcore.Level {
+	switch logLevel {
+	case logger.LogLevelPanic:
+		return zapcore.PanicLevel
+	case logger.LogLevelFatal:
+		return zapcore.FatalLevel
+	case logger.LogLevelError:
+		return zapcore.ErrorLevel
+	case logger.LogLevelWarning:
+		return zapcore.WarnLevel
+	case logger.LogLevelDebug:
+		return zapcore.DebugLevel
+	case logger.LogLevelTrace:
+		return TraceLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// core is a zapcore.Core that writes to a logger.Logger
+type core struct {
+	lg logger.Logger
+}
+
+// NewCore creates a zapcore.Core that writes to a logger.Logger. Entries are filtered by the Logger's
+// current level, the entry's logger name is split on "." into prefix segments, and zap fields become
+// structured fields. The caller is only preserved if the zap.Logger is created with zap.AddCaller.
+func NewCore(lg logger.Logger) zapcore.Core {
+	return &core{lg: lg}
+}
+
+// New creates a *zap.Logger that writes to a logger.Logger. See NewCore.
+func New(lg logger.Logger, opts ...zap.Option) *zap.Logger {
+	return zap.New(NewCore(lg), append([]zap.Option{zap.AddCaller()}, opts...)...)
+}
+
+// Enabled reports whether the Logger's level enables entries at the given zap level
+func (c *core) Enabled(level zapcore.Level) bool {
+	logLevel := LogLevelFromZap(level)
+	return logLevel <= c.lg.GetLogLevel() || logLevel <= logger.LogLevelFatal
+}
+
+// With returns a new Core whose Logger has the zap fields added as structured fields
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	if len(fields) == 0 {
+		return c
+	}
+	return &core{lg: c.lg.ForkWith(fieldsFromZap(fields))}
+}
+
+// Check adds the Core to a CheckedEntry if the entry's level is enabled
+func (c *core) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+// Write logs a zap entry to the Logger
+func (c *core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	r := &logger.Record{
+		Time:    entry.Time,
+		Level:   LogLevelFromZap(entry.Level),
+		Message: entry.Message,
+		PC:      entry.Caller.PC,
+		Fields:  fieldsFromZap(fields),
+	}
+	if entry.LoggerName != "" {
+		r.PrefixPath = strings.Split(entry.LoggerName, ".")
+	}
+	return logger.LogRecord(c.lg, r)
+}
+
+// Sync does nothing
+func (c *core) Sync() error {
+	return nil
+}
+
+// fieldsFromZap converts zap fields to logger.Fields. Fields that follow a zap.Namespace have the namespace
+// and "." prepended to their keys.
+func fieldsFromZap(fields []zapcore.Field) []logger.Field {
+	result := make([]logger.Field, 0, len(fields))
+	keyPrefix := ""
+	for _, f := range fields {
+		if f.Type == zapcore.NamespaceType {
+			keyPrefix += f.Key + "."
+			continue
+		}
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		// Most fields encode to a single key; some, such as zap.Error, may add related keys.
+		if v, ok := enc.Fields[f.Key]; ok {
+			result = append(result, logger.F(keyPrefix+f.Key, v))
+			delete(enc.Fields, f.Key)
+		}
+		keys := make([]string, 0, len(enc.Fields))
+		for k := range enc.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			result = append(result, logger.F(keyPrefix+k, enc.Fields[k]))
+		}
+	}
+	return result
+}
+
+// handler is a logger.Handler that writes Records to a *zap.Logger
+type handler struct {
+	z *zap.Logger
+}
+
+// NewHandler creates a logger.Handler that writes each Record to a *zap.Logger. The level is converted with
+// LogLevelToZap, prefix segments are added to the zap logger's name, fields are passed as zap.Any fields, and
+// the record's caller is passed as the entry's caller. Records at levels that the zap.Logger does not enable
+// are discarded. Records at LogLevelPanic and LogLevelFatal are written without panicking or exiting.
+func NewHandler(z *zap.Logger) logger.Handler {
+	return &handler{z: z}
+}
+
+// NewLogger creates a new Logger that writes to a *zap.Logger, with an optional prefix and a loglevel.
+// See NewHandler.
+func NewLogger(z *zap.Logger, prefix string, logLevel logger.LogLevel) logger.Logger {
+	return logger.NewWithHandler(NewHandler(z), prefix, logLevel)
+}
+
+// Handle converts a Record to a zap entry and writes it to the zap.Logger's Core
+func (h *handler) Handle(r *logger.Record) error {
+	names := make([]string, 0, len(r.PrefixPath)+1)
+	if h.z.Name() != "" {
+		names = append(names, h.z.Name())
+	}
+	names = append(names, r.PrefixPath...)
+	entry := zapcore.Entry{
+		Level:      LogLevelToZap(r.Level),
+		Time:       r.Time,
+		LoggerName: strings.Join(names, "."),
+		Message:    r.Message,
+	}
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		entry.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, frame.File != "")
+		entry.Caller.Function = frame.Function
+	}
+	// Checking against the Core rather than the zap.Logger bypasses the zap.Logger's panic and exit hooks
+	ce := h.z.Core().Check(entry, nil)
+	if ce == nil {
+		return nil
+	}
+	fields := make([]zapcore.Field, len(r.Fields))
+	for i, f := range r.Fields {
+		fields[i] = zap.Any(f.Key, f.Value)
+	}
+	ce.Write(fields...)
+	return nil
+}
@@ -0,0 +1,98 @@
+package zapadapter
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sammck-go/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestCore(t *testing.T) {
+	var buf bytes.Buffer
+	lg := logger.NewLogWrapper(log.New(&buf, "", log.Lshortfile), "svc", logger.LogLevelDebug)
+	z := New(lg)
+
+	_, _, line, _ := runtime.Caller(0)
+	z.Named("db").With(zap.Int("conn_id", 42)).Info("opened", zap.String("table", "users"))
+	z.Debug("debug enabled")
+	lg.SetLogLevel(logger.LogLevelInfo)
+	z.Debug("debug disabled")
+	z.With(zap.Namespace("req"), zap.Int("id", 7)).Warn("slow")
+	func() {
+		defer func() { recover() }()
+		z.Panic("boom")
+	}()
+
+	expectedLines := []string{
+		fmt.Sprintf("zapadapter_test.go:%d: svc: db: opened conn_id=42 table=users", line+1),
+		fmt.Sprintf("zapadapter_test.go:%d: svc: debug enabled", line+2),
+		fmt.Sprintf("zapadapter_test.go:%d: svc: slow req.id=7", line+5),
+		fmt.Sprintf("zapadapter_test.go:%d: svc: boom", line+8),
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(expectedLines) {
+		t.Fatalf("Expected %d lines; got %q", len(expectedLines), buf.String())
+	}
+	for i, expected := range expectedLines {
+		if lines[i] != expected {
+			t.Errorf("Expected [%s]; got [%s]", expected, lines[i])
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	obsCore, logs := observer.New(zapcore.DebugLevel)
+	lg := NewLogger(zap.New(obsCore).Named("app"), "svc", logger.LogLevelTrace)
+
+	_, _, line, _ := runtime.Caller(0)
+	lg.ForkLogStr("db").ForkWith("conn_id", 42).ILogf("opened %d", 3)
+	lg.TLog("filtered by the zap core")
+	lg.WLogw("slow", "ms", 250)
+	func() {
+		defer func() { recover() }()
+		lg.Panic("boom")
+	}()
+
+	expected := []struct {
+		level   zapcore.Level
+		name    string
+		message string
+		fields  map[string]interface{}
+		line    int
+	}{
+		{zapcore.InfoLevel, "app.svc.db", "opened 3", map[string]interface{}{"conn_id": int64(42)}, line + 1},
+		{zapcore.WarnLevel, "app.svc", "slow", map[string]interface{}{"ms": int64(250)}, line + 3},
+		{zapcore.PanicLevel, "app.svc", "boom", map[string]interface{}{}, line + 6},
+	}
+	entries := logs.AllUntimed()
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries; got %d: %v", len(expected), len(entries), entries)
+	}
+	for i, e := range expected {
+		entry := entries[i]
+		if entry.Level != e.level || entry.LoggerName != e.name || entry.Message != e.message {
+			t.Errorf("Expected %v %q %q; got %v %q %q", e.level, e.name, e.message, entry.Level, entry.LoggerName, entry.Message)
+		}
+		if fmt.Sprint(entry.ContextMap()) != fmt.Sprint(e.fields) {
+			t.Errorf("Expected fields %v; got %v", e.fields, entry.ContextMap())
+		}
+		if filepath.Base(entry.Caller.File) != "zapadapter_test.go" || entry.Caller.Line != e.line {
+			t.Errorf("Expected caller zapadapter_test.go:%d; got %s", e.line, entry.Caller.String())
+		}
+	}
+
+	for _, logLevel := range []logger.LogLevel{logger.LogLevelPanic, logger.LogLevelFatal, logger.LogLevelError,
+		logger.LogLevelWarning, logger.LogLevelInfo, logger.LogLevelDebug, logger.LogLevelTrace} {
+		if LogLevelFromZap(LogLevelToZap(logLevel)) != logLevel {
+			t.Errorf("Level %v did not survive conversion to and from zap", logLevel)
+		}
+	}
+}
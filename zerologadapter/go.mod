@@ -0,0 +1,17 @@
+module github.com/sammck-go/logger/zerologadapter
+
+go 1.23
+
+require (
+	github.com/rs/zerolog v1.35.1
+	github.com/sammck-go/logger v0.0.0-20261017014416-8404c22dd9ce
+)
+
+require (
+	github.com/mattn/go-colorable v0.1.14 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/spf13/pflag v1.0.5 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+)
+
+replace github.com/sammck-go/logger => ../
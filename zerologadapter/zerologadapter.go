@@ -0,0 +1,228 @@
+/*
+Package zerologadapter connects a logger.Logger with github.com/rs/zerolog in both directions. Writer and New
+expose a logger.Logger as a zerolog writer or zerolog.Logger, for dependencies that expect zerolog; NewHandler
+and NewLogger write the output of a logger.Logger to an existing zerolog.Logger.
+
+Zerolog has no equivalent of logger prefixes, so prefixes are carried in a field with key PrefixKey.
+*/
+package zerologadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sammck-go/logger"
+)
+
+// PrefixKey is the key of the zerolog field that carries a logger prefix
+const PrefixKey = "prefix"
+
+// LogLevelFromZerolog converts a zerolog.Level to a LogLevel. zerolog.NoLevel maps to LogLevelUnknown, which
+// is logged regardless of the Logger's level.
+func LogLevelFromZerolog(level zerolog.Level) logger.LogLevel {
+	switch level {
+	case zerolog.PanicLevel:
+		return logger.LogLevelPanic
+	case zerolog.FatalLevel:
+		return logger.LogLevelFatal
+	case zerolog.ErrorLevel:
+		return logger.LogLevelError
+	case zerolog.WarnLevel:
+		return logger.LogLevelWarning
+	case zerolog.InfoLevel:
+		return logger.LogLevelInfo
+	case zerolog.DebugLevel:
+		return logger.LogLevelDebug
+	case zerolog.TraceLevel:
+		return logger.LogLevelTrace
+	default:
+		return logger.LogLevelUnknown
+	}
+}
+
+// LogLevelToZerolog converts a LogLevel to a zerolog.Level. LogLevelUnknown maps to zerolog.NoLevel.
+func LogLevelToZerolog(logLevel logger.LogLevel) zerolog.Level {
+	switch logLevel {
+	case logger.LogLevelPanic:
+		return zerolog.PanicLevel
+	case logger.LogLevelFatal:
+		return zerolog.FatalLevel
+	case logger.LogLevelError:
+		return zerolog.ErrorLevel
+	case logger.LogLevelWarning:
+		return zerolog.WarnLevel
+	case logger.LogLevelInfo:
+		return zerolog.InfoLevel
+	case logger.LogLevelDebug:
+		return zerolog.DebugLevel
+	case logger.LogLevelTrace:
+		return zerolog.TraceLevel
+	default:
+		return zerolog.NoLevel
+	}
+}
+
+// Writer is a zerolog.LevelWriter that decodes zerolog's JSON events and writes them to a logger.Logger.
+// Events are filtered by the Logger's current level. The zerolog level, message and timestamp fields become
+// the record's level, message and time, the field with key PrefixKey (if any) becomes a prefix segment, and
+// the other fields become structured fields in their original order. The caller is the first frame on the
+// stack outside of zerolog and this package.
+type Writer struct {
+	lg logger.Logger
+}
+
+// NewWriter creates a zerolog.LevelWriter that writes zerolog events to a logger.Logger
+func NewWriter(lg logger.Logger) *Writer {
+	w := &Writer{
+		lg: lg,
+	}
+	return w
+}
+
+// New creates a zerolog.Logger with timestamps that writes to a logger.Logger. The zerolog level is set to
+// zerolog.TraceLevel, so that filtering is determined by the Logger's current level.
+func New(lg logger.Logger) zerolog.Logger {
+	return zerolog.New(NewWriter(lg)).Level(zerolog.TraceLevel).With().Timestamp().Logger()
+}
+
+// Write writes a zerolog event whose level is given by its level field
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel writes a zerolog event at a given level
+func (w *Writer) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	r := &logger.Record{
+		Level: LogLevelFromZerolog(level),
+		PC:    callerPC(),
+	}
+	err := decodeEvent(p, func(key string, value interface{}) {
+		switch key {
+		case zerolog.LevelFieldName:
+			if level == zerolog.NoLevel {
+				if s, ok := value.(string); ok {
+					parsed, err := zerolog.ParseLevel(s)
+					if err == nil {
+						r.Level = LogLevelFromZerolog(parsed)
+					}
+				}
+			}
+		case zerolog.MessageFieldName:
+			r.Message = fmt.Sprint(value)
+		case zerolog.TimestampFieldName:
+			if s, ok := value.(string); ok {
+				t, err := time.Parse(zerolog.TimeFieldFormat, s)
+				if err == nil {
+					r.Time = t
+					return
+				}
+			}
+			r.Fields = append(r.Fields, logger.F(key, value))
+		case PrefixKey:
+			r.PrefixPath = []string{fmt.Sprint(value)}
+		default:
+			r.Fields = append(r.Fields, logger.F(key, value))
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), logger.LogRecord(w.lg, r)
+}
+
+// decodeEvent decodes a JSON object, calling fn for each key and value in order. Numbers are decoded as
+// int64 if possible, and otherwise as float64.
+func decodeEvent(p []byte, fn func(key string, value interface{})) error {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("zerolog event is not a JSON object")
+	}
+	for dec.More() {
+		tok, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		var value interface{}
+		err = dec.Decode(&value)
+		if err != nil {
+			return err
+		}
+		if n, ok := value.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				value = i
+			} else if f, err := n.Float64(); err == nil {
+				value = f
+			}
+		}
+		fn(key, value)
+	}
+	return nil
+}
+
+// callerPC returns the PC of the first frame on the stack that is not in zerolog or this file
+func callerPC() uintptr {
+	_, thisFile, _, _ := runtime.Caller(0)
+	var pcs [64]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "github.com/rs/zerolog") && frame.File != thisFile {
+			return frame.PC
+		}
+		if !more {
+			return 0
+		}
+	}
+}
+
+// handler is a logger.Handler that writes Records to a zerolog.Logger
+type handler struct {
+	zl zerolog.Logger
+}
+
+// NewHandler creates a logger.Handler that writes each Record to a zerolog.Logger. The level is converted
+// with LogLevelToZerolog, the prefix is passed as a field with key PrefixKey, and fields are passed as zerolog
+// fields. The record's time is not passed; use zerolog's Timestamp to add timestamps. Records at levels that
+// the zerolog.Logger does not enable are discarded. Records at LogLevelPanic and LogLevelFatal are written
+// without panicking or exiting.
+func NewHandler(zl zerolog.Logger) logger.Handler {
+	return &handler{zl: zl}
+}
+
+// NewLogger creates a new Logger that writes to a zerolog.Logger, with an optional prefix and a loglevel.
+// See NewHandler.
+func NewLogger(zl zerolog.Logger, prefix string, logLevel logger.LogLevel) logger.Logger {
+	return logger.NewWithHandler(NewHandler(zl), prefix, logLevel)
+}
+
+// Handle converts a Record to a zerolog event and sends it
+func (h *handler) Handle(r *logger.Record) error {
+	e := h.zl.WithLevel(LogLevelToZerolog(r.Level))
+	if e == nil {
+		return nil
+	}
+	if len(r.PrefixPath) > 0 {
+		e = e.Str(PrefixKey, r.Prefix())
+	}
+	for _, f := range r.Fields {
+		if err, ok := f.Value.(error); ok {
+			e = e.AnErr(f.Key, err)
+		} else {
+			e = e.Interface(f.Key, f.Value)
+		}
+	}
+	e.Msg(r.Message)
+	return nil
+}
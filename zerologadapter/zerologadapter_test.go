@@ -0,0 +1,83 @@
+package zerologadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/sammck-go/logger"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	lg := logger.NewLogWrapper(log.New(&buf, "", log.Lshortfile), "svc", logger.LogLevelDebug)
+	zl := New(lg)
+
+	_, _, line, _ := runtime.Caller(0)
+	zl.Info().Str(PrefixKey, "db").Int("conn_id", 42).Str("table", "users").Msg("opened")
+	zl.Debug().Msgf("debug %s", "enabled")
+	lg.SetLogLevel(logger.LogLevelInfo)
+	zl.Debug().Msg("debug disabled")
+	zl.Warn().Err(errors.New("timeout")).Float64("ratio", 0.5).Msg("slow")
+	func() {
+		defer func() { recover() }()
+		zl.Panic().Msg("boom")
+	}()
+
+	expectedLines := []string{
+		fmt.Sprintf("zerologadapter_test.go:%d: svc: db: opened conn_id=42 table=users", line+1),
+		fmt.Sprintf("zerologadapter_test.go:%d: svc: debug enabled", line+2),
+		fmt.Sprintf("zerologadapter_test.go:%d: svc: slow error=timeout ratio=0.5", line+5),
+		fmt.Sprintf("zerologadapter_test.go:%d: svc: boom", line+8),
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(expectedLines) {
+		t.Fatalf("Expected %d lines; got %q", len(expectedLines), buf.String())
+	}
+	for i, expected := range expectedLines {
+		if lines[i] != expected {
+			t.Errorf("Expected [%s]; got [%s]", expected, lines[i])
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	lg := NewLogger(zerolog.New(&buf).Level(zerolog.DebugLevel), "svc", logger.LogLevelTrace)
+
+	lg.ForkLogStr("db").ForkWith("conn_id", 42).ILogf("opened %d", 3)
+	lg.TLog("filtered by the zerolog logger")
+	lg.WLogw("slow", "err", errors.New("timeout"))
+	func() {
+		defer func() { recover() }()
+		lg.Panic("boom")
+	}()
+
+	expectedLines := []string{
+		`{"level":"info","prefix":"svc: db","conn_id":42,"message":"opened 3"}`,
+		`{"level":"warn","prefix":"svc","err":"timeout","message":"slow"}`,
+		`{"level":"panic","prefix":"svc","message":"boom"}`,
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(expectedLines) {
+		t.Fatalf("Expected %d lines; got %q", len(expectedLines), buf.String())
+	}
+	for i, expected := range expectedLines {
+		if lines[i] != expected || !json.Valid([]byte(lines[i])) {
+			t.Errorf("Expected [%s]; got [%s]", expected, lines[i])
+		}
+	}
+
+	for _, logLevel := range []logger.LogLevel{logger.LogLevelUnknown, logger.LogLevelPanic, logger.LogLevelFatal,
+		logger.LogLevelError, logger.LogLevelWarning, logger.LogLevelInfo, logger.LogLevelDebug, logger.LogLevelTrace} {
+		if LogLevelFromZerolog(LogLevelToZerolog(logLevel)) != logLevel {
+			t.Errorf("Level %v did not survive conversion to and from zerolog", logLevel)
+		}
+	}
+}